@@ -20,6 +20,7 @@ CREATE TABLE `blog_article` (
   `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
   `tag_id` int(10) unsigned DEFAULT '0' COMMENT '标签ID',
   `title` varchar(100) DEFAULT '' COMMENT '文章标题',
+  `slug` varchar(255) DEFAULT '' COMMENT '文章别名',
   `desc` varchar(255) DEFAULT '' COMMENT '简述',
   `content` text COMMENT '内容',
   `cover_image_url` varchar(255) DEFAULT '' COMMENT '封面图片地址',
@@ -29,9 +30,33 @@ CREATE TABLE `blog_article` (
   `modified_by` varchar(255) DEFAULT '' COMMENT '修改人',
   `deleted_on` int(10) unsigned DEFAULT '0',
   `state` tinyint(3) unsigned DEFAULT '1' COMMENT '删除时间',
-  PRIMARY KEY (`id`)
+  `visibility` varchar(20) DEFAULT 'public' COMMENT '可见性 public、unlisted、private、password',
+  `password_hash` varchar(255) DEFAULT '' COMMENT '访问密码哈希',
+  `lang` varchar(20) DEFAULT '' COMMENT '原文语言',
+  PRIMARY KEY (`id`),
+  KEY `idx_blog_article_slug` (`slug`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='文章管理';
 
+-- ----------------------------
+-- Table structure for blog_article_translation
+-- ----------------------------
+DROP TABLE IF EXISTS `blog_article_translation`;
+CREATE TABLE `blog_article_translation` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `article_id` int(10) unsigned DEFAULT '0' COMMENT '文章ID',
+  `lang` varchar(20) DEFAULT '' COMMENT '译文语言',
+  `title` varchar(100) DEFAULT '' COMMENT '文章标题',
+  `desc` varchar(255) DEFAULT '' COMMENT '简述',
+  `content` text COMMENT '内容',
+  `created_on` int(10) unsigned DEFAULT '0' COMMENT '创建时间',
+  `created_by` varchar(100) DEFAULT '' COMMENT '创建人',
+  `modified_on` int(10) unsigned DEFAULT '0' COMMENT '修改时间',
+  `modified_by` varchar(100) DEFAULT '' COMMENT '修改人',
+  `deleted_on` int(10) unsigned DEFAULT '0',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uix_blog_article_translation_article_lang` (`article_id`,`lang`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='文章译文';
+
 -- ----------------------------
 -- Table structure for blog_auth
 -- ----------------------------
@@ -45,6 +70,55 @@ CREATE TABLE `blog_auth` (
 
 INSERT INTO `blog_auth` (`id`, `username`, `password`) VALUES ('1', 'test', 'test123');
 
+-- ----------------------------
+-- Table structure for blog_author
+-- ----------------------------
+DROP TABLE IF EXISTS `blog_author`;
+CREATE TABLE `blog_author` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `auth_id` int(10) unsigned DEFAULT '0' COMMENT '账号ID',
+  `display_name` varchar(100) DEFAULT '' COMMENT '显示名称',
+  `bio` varchar(1000) DEFAULT '' COMMENT '简介',
+  `avatar_url` varchar(255) DEFAULT '' COMMENT '头像地址',
+  `created_on` int(10) unsigned DEFAULT '0' COMMENT '创建时间',
+  `created_by` varchar(100) DEFAULT '' COMMENT '创建人',
+  `modified_on` int(10) unsigned DEFAULT '0' COMMENT '修改时间',
+  `modified_by` varchar(100) DEFAULT '' COMMENT '修改人',
+  `deleted_on` int(10) unsigned DEFAULT '0',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uix_blog_author_auth_id` (`auth_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='作者资料';
+
+-- ----------------------------
+-- Table structure for blog_series
+-- ----------------------------
+DROP TABLE IF EXISTS `blog_series`;
+CREATE TABLE `blog_series` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) DEFAULT '' COMMENT '系列名称',
+  `desc` varchar(255) DEFAULT '' COMMENT '简述',
+  `created_on` int(10) unsigned DEFAULT '0' COMMENT '创建时间',
+  `created_by` varchar(100) DEFAULT '' COMMENT '创建人',
+  `modified_on` int(10) unsigned DEFAULT '0' COMMENT '修改时间',
+  `modified_by` varchar(100) DEFAULT '' COMMENT '修改人',
+  `deleted_on` int(10) unsigned DEFAULT '0' COMMENT '删除时间',
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='文章系列管理';
+
+-- ----------------------------
+-- Table structure for blog_series_article
+-- ----------------------------
+DROP TABLE IF EXISTS `blog_series_article`;
+CREATE TABLE `blog_series_article` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `series_id` int(10) unsigned DEFAULT '0' COMMENT '系列ID',
+  `article_id` int(10) unsigned DEFAULT '0' COMMENT '文章ID',
+  `position` int(10) unsigned DEFAULT '0' COMMENT '系列中的顺序',
+  PRIMARY KEY (`id`),
+  KEY `idx_blog_series_article_series_id` (`series_id`),
+  UNIQUE KEY `uix_blog_series_article_article_id` (`article_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='系列文章';
+
 -- ----------------------------
 -- Table structure for blog_tag
 -- ----------------------------
@@ -52,6 +126,10 @@ DROP TABLE IF EXISTS `blog_tag`;
 CREATE TABLE `blog_tag` (
   `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
   `name` varchar(100) DEFAULT '' COMMENT '标签名称',
+  `description` varchar(255) DEFAULT '' COMMENT '描述',
+  `color` varchar(7) DEFAULT '' COMMENT '显示颜色，如 #1e90ff',
+  `cover_image_url` varchar(255) DEFAULT '' COMMENT '封面图片地址',
+  `sort_order` int(10) unsigned DEFAULT '0' COMMENT '排序，越小越靠前',
   `created_on` int(10) unsigned DEFAULT '0' COMMENT '创建时间',
   `created_by` varchar(100) DEFAULT '' COMMENT '创建人',
   `modified_on` int(10) unsigned DEFAULT '0' COMMENT '修改时间',
@@ -61,3 +139,48 @@ CREATE TABLE `blog_tag` (
   PRIMARY KEY (`id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='文章标签管理';
 
+-- ----------------------------
+-- Table structure for blog_tag_alias
+-- ----------------------------
+DROP TABLE IF EXISTS `blog_tag_alias`;
+CREATE TABLE `blog_tag_alias` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) DEFAULT '' COMMENT '别名',
+  `tag_id` int(10) unsigned DEFAULT '0' COMMENT '标签ID',
+  `created_on` int(10) unsigned DEFAULT '0' COMMENT '创建时间',
+  `created_by` varchar(100) DEFAULT '' COMMENT '创建人',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uix_blog_tag_alias_name` (`name`),
+  KEY `idx_blog_tag_alias_tag_id` (`tag_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='标签别名';
+
+-- ----------------------------
+-- Table structure for blog_upload
+-- ----------------------------
+DROP TABLE IF EXISTS `blog_upload`;
+CREATE TABLE `blog_upload` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) DEFAULT '' COMMENT '文件名',
+  `path` varchar(255) DEFAULT '' COMMENT '保存路径',
+  `size` bigint(20) unsigned DEFAULT '0' COMMENT '文件大小',
+  `created_on` int(10) unsigned DEFAULT '0' COMMENT '上传时间',
+  `created_by` varchar(100) DEFAULT '' COMMENT '上传人',
+  `modified_on` int(10) unsigned DEFAULT '0' COMMENT '修改时间',
+  `deleted_on` int(10) unsigned DEFAULT '0' COMMENT '删除时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uix_blog_upload_name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='上传文件管理';
+
+-- ----------------------------
+-- Table structure for blog_upload_ref
+-- ----------------------------
+DROP TABLE IF EXISTS `blog_upload_ref`;
+CREATE TABLE `blog_upload_ref` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) DEFAULT '' COMMENT '文件名',
+  `ref_type` varchar(50) DEFAULT '' COMMENT '引用类型',
+  `ref_id` int(10) unsigned DEFAULT '0' COMMENT '引用ID',
+  PRIMARY KEY (`id`),
+  KEY `idx_blog_upload_ref_name` (`name`),
+  KEY `idx_blog_upload_ref_ref` (`ref_type`,`ref_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='上传文件引用';
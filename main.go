@@ -13,6 +13,8 @@ import (
 	"github.com/EDDYCJY/go-gin-example/pkg/setting"
 	"github.com/EDDYCJY/go-gin-example/routers"
 	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/export_service"
+	"github.com/EDDYCJY/go-gin-example/service/upload_service"
 )
 
 func init() {
@@ -32,6 +34,9 @@ func init() {
 func main() {
 	gin.SetMode(setting.ServerSetting.RunMode)
 
+	upload_service.Schedule(setting.AppSetting.ImageCleanInterval, setting.AppSetting.ImageGracePeriod)
+	export_service.Schedule(setting.AppSetting.ExportCleanInterval, setting.AppSetting.ExportExpire)
+
 	routersInit := routers.InitRouter()
 	readTimeout := setting.ServerSetting.ReadTimeout
 	writeTimeout := setting.ServerSetting.WriteTimeout
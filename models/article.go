@@ -1,9 +1,24 @@
 package models
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/jinzhu/gorm"
 )
 
+const (
+	VISIBILITY_PUBLIC   = "public"
+	VISIBILITY_UNLISTED = "unlisted"
+	VISIBILITY_PRIVATE  = "private"
+	VISIBILITY_PASSWORD = "password"
+)
+
+// NewPublicCond restricts the articles to the public ones, the ones stored before visibilities existed having none
+func NewPublicCond() *Cond {
+	return NewCond("visibility IN (?)", []string{VISIBILITY_PUBLIC, ""})
+}
+
 type Article struct {
 	Model
 
@@ -11,12 +26,20 @@ type Article struct {
 	Tag   Tag `json:"tag"`
 
 	Title         string `json:"title"`
+	Slug          string `json:"slug" gorm:"index"`
 	Desc          string `json:"desc"`
 	Content       string `json:"content"`
 	CoverImageUrl string `json:"cover_image_url"`
 	CreatedBy     string `json:"created_by"`
 	ModifiedBy    string `json:"modified_by"`
 	State         int    `json:"state"`
+	Visibility    string `json:"visibility"`
+	PasswordHash  string `json:"-"`
+	Lang          string `json:"lang"`
+
+	// Author is nil for the usernames without a profile and left out of the series, so the articles keep
+	// created_by as well; the cached articles also need it to tell who manages them
+	Author *ArticleAuthor `json:"author" gorm:"-"`
 }
 
 // ExistArticleByID checks if an article exists based on ID
@@ -34,10 +57,43 @@ func ExistArticleByID(id int) (bool, error) {
 	return false, nil
 }
 
+// GetExistingArticleIDs gets the ids among the given ones whose article is not deleted
+func GetExistingArticleIDs(ids []int) ([]int, error) {
+	var existing []int
+	err := db.Model(&Article{}).Where("id IN (?) AND deleted_on = ? ", ids, 0).Pluck("id", &existing).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// GetArticleOwners gets the author and visibility of the articles, deleted ones included
+func GetArticleOwners(ids []int) ([]*Article, error) {
+	var articles []*Article
+	err := db.Select("id, created_by, visibility").Where("id IN (?)", ids).Find(&articles).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// GetArticleBySlug gets a single article based on its slug
+func GetArticleBySlug(slug string) (*Article, error) {
+	var article Article
+	err := db.Where("slug = ? AND deleted_on = ? ", slug, 0).First(&article).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &article, nil
+}
+
 // GetArticleTotal gets the total number of articles based on the constraints
-func GetArticleTotal(maps interface{}) (int, error) {
+func GetArticleTotal(maps interface{}, conds ...*Cond) (int, error) {
 	var count int
-	if err := db.Model(&Article{}).Where(maps).Count(&count).Error; err != nil {
+	if err := where(db.Model(&Article{}), maps, conds).Count(&count).Error; err != nil {
 		return 0, err
 	}
 
@@ -45,9 +101,68 @@ func GetArticleTotal(maps interface{}) (int, error) {
 }
 
 // GetArticles gets a list of articles based on paging constraints
-func GetArticles(pageNum int, pageSize int, maps interface{}) ([]*Article, error) {
+func GetArticles(pageNum int, pageSize int, maps interface{}, conds ...*Cond) ([]*Article, error) {
+	var articles []*Article
+	query := where(db.Preload("Tag"), maps, conds)
+	if pageSize > 0 {
+		query = query.Offset(pageNum).Limit(pageSize)
+	}
+
+	err := query.Find(&articles).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// GetArticleCreatedOns gets the creation time of every article based on the constraints
+func GetArticleCreatedOns(maps interface{}, conds ...*Cond) ([]int, error) {
+	var createdOns []int
+	if err := where(db.Model(&Article{}), maps, conds).Pluck("created_on", &createdOns).Error; err != nil {
+		return nil, err
+	}
+
+	return createdOns, nil
+}
+
+// GetArticlesAfter gets the articles, deleted ones included, following the given ID in ID order
+func GetArticlesAfter(id, limit int) ([]*Article, error) {
 	var articles []*Article
-	err := db.Preload("Tag").Where(maps).Offset(pageNum).Limit(pageSize).Find(&articles).Error
+	err := db.Where("id > ?", id).Order("id").Limit(limit).Find(&articles).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// GetRecentArticles gets the latest articles based on the constraints
+func GetRecentArticles(limit int, maps interface{}, conds ...*Cond) ([]*Article, error) {
+	var articles []*Article
+	err := where(db.Preload("Tag"), maps, conds).Order("created_on DESC").Limit(limit).Find(&articles).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// GetArticleLastModified gets the latest modification time of articles based on the constraints
+func GetArticleLastModified(maps interface{}, conds ...*Cond) (int, error) {
+	var modifiedOn int
+	row := where(db.Model(&Article{}).Select("COALESCE(MAX(modified_on), 0)"), maps, conds).Row()
+	if err := row.Scan(&modifiedOn); err != nil {
+		return 0, err
+	}
+
+	return modifiedOn, nil
+}
+
+// GetArticleLastMods gets the id and modification time of every article based on the constraints
+func GetArticleLastMods(maps interface{}, conds ...*Cond) ([]*Article, error) {
+	var articles []*Article
+	err := where(db.Select("id, modified_on"), maps, conds).Order("id").Find(&articles).Error
 	if err != nil && err != gorm.ErrRecordNotFound {
 		return nil, err
 	}
@@ -72,30 +187,126 @@ func GetArticle(id int) (*Article, error) {
 }
 
 // EditArticle modify a single article
-func EditArticle(id int, data interface{}) error {
-	if err := db.Model(&Article{}).Where("id = ? AND deleted_on = ? ", id, 0).Updates(data).Error; err != nil {
+func EditArticle(id int, data map[string]interface{}) error {
+	if err := db.Model(&Article{}).Where("id = ? AND deleted_on = ? ", id, 0).UpdateColumns(withNextVersion(data)).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// AddArticle add a single article
-func AddArticle(data map[string]interface{}) error {
-	article := Article{
+// EditArticleIfUnmodified modify a single article only if its modification time still equals modifiedOn,
+// it returns the new modification time, or 0 if the article has been changed in the meantime
+func EditArticleIfUnmodified(id, modifiedOn int, data map[string]interface{}) (int, error) {
+	version := int(time.Now().Unix())
+	if version <= modifiedOn {
+		version = modifiedOn + 1
+	}
+
+	columns := map[string]interface{}{"modified_on": version}
+	for k, v := range data {
+		columns[k] = v
+	}
+
+	res := db.Model(&Article{}).Where("id = ? AND deleted_on = ? AND modified_on = ?", id, 0, modifiedOn).UpdateColumns(columns)
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return 0, nil
+	}
+
+	return version, nil
+}
+
+// withNextVersion adds to the columns of an edit the modification time of the articles, now or one second
+// past their current one if they were already modified within this second, so that their ETag always changes
+func withNextVersion(data map[string]interface{}) map[string]interface{} {
+	columns := map[string]interface{}{"modified_on": gorm.Expr("GREATEST(?, modified_on + 1)", time.Now().Unix())}
+	for k, v := range data {
+		columns[k] = v
+	}
+
+	return columns
+}
+
+// AddArticle add a single article and returns its ID
+func AddArticle(data map[string]interface{}) (int, error) {
+	article := newArticle(data)
+	if err := db.Create(article).Error; err != nil {
+		return 0, err
+	}
+
+	return article.ID, nil
+}
+
+func newArticle(data map[string]interface{}) *Article {
+	article := &Article{
 		TagID:         data["tag_id"].(int),
 		Title:         data["title"].(string),
+		Slug:          data["slug"].(string),
 		Desc:          data["desc"].(string),
 		Content:       data["content"].(string),
 		CreatedBy:     data["created_by"].(string),
 		State:         data["state"].(int),
 		CoverImageUrl: data["cover_image_url"].(string),
+		Visibility:    VISIBILITY_PUBLIC,
 	}
-	if err := db.Create(&article).Error; err != nil {
-		return err
+	if visibility, ok := data["visibility"].(string); ok && visibility != "" {
+		article.Visibility = visibility
+	}
+	if passwordHash, ok := data["password_hash"].(string); ok {
+		article.PasswordHash = passwordHash
+	}
+	if lang, ok := data["lang"].(string); ok {
+		article.Lang = lang
+	}
+	if createdOn, ok := data["created_on"].(int); ok {
+		article.CreatedOn = createdOn
 	}
 
-	return nil
+	return article
+}
+
+// ArticleImport is an article to add, or to modify when its ID is set
+type ArticleImport struct {
+	ID   int
+	Data map[string]interface{}
+}
+
+// ImportArticles add and modify the articles in a single transaction, setting the IDs of the added ones;
+// it fails without changing anything if a slug is already used by another article
+func ImportArticles(imports []*ArticleImport) error {
+	return transaction(func(tx *gorm.DB) error {
+		for _, v := range imports {
+			if slug, _ := v.Data["slug"].(string); slug != "" {
+				var count int
+				err := tx.Model(&Article{}).Where("slug = ? AND id != ? AND deleted_on = ?", slug, v.ID, 0).Count(&count).Error
+				if err != nil {
+					return err
+				}
+				if count > 0 {
+					return fmt.Errorf("slug %q is already used", slug)
+				}
+			}
+
+			if v.ID > 0 {
+				err := tx.Model(&Article{}).Where("id = ? AND deleted_on = ? ", v.ID, 0).UpdateColumns(withNextVersion(v.Data)).Error
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			article := newArticle(v.Data)
+			if err := tx.Create(article).Error; err != nil {
+				return err
+			}
+			v.ID = article.ID
+		}
+
+		return nil
+	})
 }
 
 // DeleteArticle delete a single article
@@ -107,6 +318,37 @@ func DeleteArticle(id int) error {
 	return nil
 }
 
+// BatchEditArticles modify the articles in a single transaction
+func BatchEditArticles(ids []int, data map[string]interface{}) ([]*BatchResult, error) {
+	return batch(&Article{}, ids, false, func(tx *gorm.DB, ids []int) error {
+		return tx.Model(&Article{}).Where("id IN (?)", ids).UpdateColumns(withNextVersion(data)).Error
+	})
+}
+
+// BatchDeleteArticles delete the articles in a single transaction
+func BatchDeleteArticles(ids []int) ([]*BatchResult, error) {
+	return batch(&Article{}, ids, false, func(tx *gorm.DB, ids []int) error {
+		return tx.Where("id IN (?)", ids).Delete(Article{}).Error
+	})
+}
+
+// BatchRestoreArticles restore the deleted articles in a single transaction
+func BatchRestoreArticles(ids []int) ([]*BatchResult, error) {
+	return batch(&Article{}, ids, true, func(tx *gorm.DB, ids []int) error {
+		return tx.Model(&Article{}).Where("id IN (?)", ids).Update("deleted_on", 0).Error
+	})
+}
+
+// DeleteArticleIfUnmodified delete a single article only if its modification time still equals modifiedOn
+func DeleteArticleIfUnmodified(id, modifiedOn int) (bool, error) {
+	res := db.Where("id = ? AND deleted_on = ? AND modified_on = ?", id, 0, modifiedOn).Delete(Article{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
 // CleanAllArticle clear all article
 func CleanAllArticle() error {
 	if err := db.Unscoped().Where("deleted_on != ? ", 0).Delete(&Article{}).Error; err != nil {
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ArticleTranslation is the title, desc and content of an article in another language than its own
+type ArticleTranslation struct {
+	Model
+
+	ArticleID  int    `json:"article_id" gorm:"unique_index:uix_blog_article_translation_article_lang"`
+	Lang       string `json:"lang" gorm:"unique_index:uix_blog_article_translation_article_lang"`
+	Title      string `json:"title"`
+	Desc       string `json:"desc"`
+	Content    string `json:"content"`
+	CreatedBy  string `json:"created_by"`
+	ModifiedBy string `json:"modified_by"`
+}
+
+// GetArticleTranslation gets the translation of an article in the language, its ID is 0 if there is none
+func GetArticleTranslation(articleID int, lang string) (*ArticleTranslation, error) {
+	var translation ArticleTranslation
+	err := db.Where("article_id = ? AND lang = ?", articleID, lang).First(&translation).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &translation, nil
+}
+
+// GetArticleTranslations gets every translation of an article
+func GetArticleTranslations(articleID int) ([]*ArticleTranslation, error) {
+	var translations []*ArticleTranslation
+	err := db.Where("article_id = ?", articleID).Order("lang").Find(&translations).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return translations, nil
+}
+
+// GetTranslationsByLang gets the translations of the articles in the language, by article ID
+func GetTranslationsByLang(articleIDs []int, lang string) (map[int]*ArticleTranslation, error) {
+	var translations []*ArticleTranslation
+	err := db.Where("article_id IN (?) AND lang = ?", articleIDs, lang).Find(&translations).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	byArticle := make(map[int]*ArticleTranslation, len(translations))
+	for _, translation := range translations {
+		byArticle[translation.ArticleID] = translation
+	}
+
+	return byArticle, nil
+}
+
+// NewLangCond restricts articles to the ones written in one of the languages or translated into lang
+func NewLangCond(langs []string, lang string) *Cond {
+	return NewCond(fmt.Sprintf("(lang IN (?) OR id IN (SELECT article_id FROM %s WHERE lang = ?))",
+		db.NewScope(&ArticleTranslation{}).QuotedTableName()), langs, lang)
+}
+
+// SaveArticleTranslation adds or replaces the translation of an article in a language, returns its ID
+func SaveArticleTranslation(data map[string]interface{}) (int, error) {
+	translation, err := GetArticleTranslation(data["article_id"].(int), data["lang"].(string))
+	if err != nil {
+		return 0, err
+	}
+
+	if translation.ID > 0 {
+		err = db.Model(translation).Updates(map[string]interface{}{
+			"title":       data["title"],
+			"desc":        data["desc"],
+			"content":     data["content"],
+			"modified_by": data["modified_by"],
+		}).Error
+		return translation.ID, err
+	}
+
+	translation = &ArticleTranslation{
+		ArticleID: data["article_id"].(int),
+		Lang:      data["lang"].(string),
+		Title:     data["title"].(string),
+		Desc:      data["desc"].(string),
+		Content:   data["content"].(string),
+		CreatedBy: data["modified_by"].(string),
+	}
+	if err := db.Create(translation).Error; err != nil {
+		return 0, err
+	}
+
+	return translation.ID, nil
+}
+
+// DeleteArticleTranslation removes the translation of an article in a language, returns the ID it had
+func DeleteArticleTranslation(articleID int, lang string) (int, error) {
+	translation, err := GetArticleTranslation(articleID, lang)
+	if err != nil || translation.ID == 0 {
+		return 0, err
+	}
+
+	return translation.ID, db.Unscoped().Where("id = ?", translation.ID).Delete(&ArticleTranslation{}).Error
+}
@@ -22,3 +22,14 @@ func CheckAuth(username, password string) (bool, error) {
 
 	return false, nil
 }
+
+// GetAuth gets a single account based on ID
+func GetAuth(id int) (*Auth, error) {
+	var auth Auth
+	err := db.Where("id = ?", id).First(&auth).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &auth, nil
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Author is the public profile of an account
+type Author struct {
+	Model
+
+	AuthID      int    `json:"auth_id" gorm:"unique_index"`
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+	AvatarUrl   string `json:"avatar_url"`
+	CreatedBy   string `json:"created_by"`
+	ModifiedBy  string `json:"modified_by"`
+}
+
+// ArticleAuthor is the compact profile embedded in articles, found by the username the articles are created by
+type ArticleAuthor struct {
+	ID          int    `json:"id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	AvatarUrl   string `json:"avatar_url"`
+}
+
+// ExistAuthorByID checks if an author exists based on ID
+func ExistAuthorByID(id int) (bool, error) {
+	var author Author
+	err := db.Select("id").Where("id = ? AND deleted_on = ? ", id, 0).First(&author).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+
+	return author.ID > 0, nil
+}
+
+// ExistAuthorByAuthID checks if the account already has a profile
+func ExistAuthorByAuthID(authID int) (bool, error) {
+	var author Author
+	err := db.Select("id").Where("auth_id = ? AND deleted_on = ? ", authID, 0).First(&author).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+
+	return author.ID > 0, nil
+}
+
+// GetAuthor gets a single author based on ID
+func GetAuthor(id int) (*Author, error) {
+	var author Author
+	err := db.Where("id = ? AND deleted_on = ? ", id, 0).First(&author).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &author, nil
+}
+
+// GetArticleAuthors gets the profiles of the accounts with the given usernames, by username
+func GetArticleAuthors(usernames []string) (map[string]*ArticleAuthor, error) {
+	var authors []*ArticleAuthor
+	authorTable := db.NewScope(&Author{}).QuotedTableName()
+	authTable := db.NewScope(&Auth{}).QuotedTableName()
+	err := db.Table(authorTable).
+		Select(fmt.Sprintf("%s.id, %s.username, %s.display_name, %s.avatar_url", authorTable, authTable, authorTable, authorTable)).
+		Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.auth_id", authTable, authTable, authorTable)).
+		Where(fmt.Sprintf("%s.username IN (?) AND %s.deleted_on = ?", authTable, authorTable), usernames, 0).
+		Scan(&authors).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	byUsername := make(map[string]*ArticleAuthor, len(authors))
+	for _, author := range authors {
+		byUsername[author.Username] = author
+	}
+
+	return byUsername, nil
+}
+
+// AddAuthor add a single author and returns its ID
+func AddAuthor(data map[string]interface{}) (int, error) {
+	author := Author{
+		AuthID:      data["auth_id"].(int),
+		DisplayName: data["display_name"].(string),
+		Bio:         data["bio"].(string),
+		AvatarUrl:   data["avatar_url"].(string),
+		CreatedBy:   data["created_by"].(string),
+	}
+	if err := db.Create(&author).Error; err != nil {
+		return 0, err
+	}
+
+	return author.ID, nil
+}
+
+// EditAuthor modify a single author
+func EditAuthor(id int, data interface{}) error {
+	return db.Model(&Author{}).Where("id = ? AND deleted_on = ? ", id, 0).Updates(data).Error
+}
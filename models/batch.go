@@ -0,0 +1,93 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	BATCH_OK        = "ok"
+	BATCH_NOT_FOUND = "not_found"
+	BATCH_IN_USE    = "in_use"
+)
+
+// BatchResult is the outcome of a batch operation for a single ID
+type BatchResult struct {
+	ID     int    `json:"id"`
+	Result string `json:"result"`
+}
+
+// transaction runs fc in a database transaction, rolling back if it fails
+func transaction(fc func(tx *gorm.DB) error) (err error) {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err = fc(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// batch locks the rows of model matching ids, applies fc to the ones found and reports the result of each ID
+func batch(model interface{}, ids []int, deleted bool, fc func(tx *gorm.DB, ids []int) error) ([]*BatchResult, error) {
+	var results []*BatchResult
+	err := transaction(func(tx *gorm.DB) error {
+		cond := "deleted_on = 0"
+		if deleted {
+			cond = "deleted_on != 0"
+		}
+
+		rows, err := tx.Raw(fmt.Sprintf("SELECT id FROM %s WHERE id IN (?) AND %s FOR UPDATE",
+			tx.NewScope(model).QuotedTableName(), cond), ids).Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		var found []int
+		exists := make(map[int]bool)
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			found = append(found, id)
+			exists[id] = true
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		results = make([]*BatchResult, 0, len(ids))
+		for _, id := range ids {
+			result := BATCH_NOT_FOUND
+			if exists[id] {
+				result = BATCH_OK
+			}
+			results = append(results, &BatchResult{ID: id, Result: result})
+		}
+
+		if len(found) == 0 {
+			return nil
+		}
+
+		return fc(tx, found)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
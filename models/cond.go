@@ -0,0 +1,24 @@
+package models
+
+import "github.com/jinzhu/gorm"
+
+// Cond is a condition with placeholders, for the constraints that an equality map cannot express
+type Cond struct {
+	Query string
+	Args  []interface{}
+}
+
+// NewCond creates a condition from a query and its arguments
+func NewCond(query string, args ...interface{}) *Cond {
+	return &Cond{Query: query, Args: args}
+}
+
+// where applies the equality map and the conditions to the query
+func where(query *gorm.DB, maps interface{}, conds []*Cond) *gorm.DB {
+	query = query.Where(maps)
+	for _, cond := range conds {
+		query = query.Where(cond.Query, cond.Args...)
+	}
+
+	return query
+}
@@ -0,0 +1,185 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+)
+
+type Series struct {
+	Model
+
+	Name       string `json:"name"`
+	Desc       string `json:"desc"`
+	CreatedBy  string `json:"created_by"`
+	ModifiedBy string `json:"modified_by"`
+}
+
+// SeriesArticle places an article in a series, an article belongs to one series at most
+type SeriesArticle struct {
+	ID        int `gorm:"primary_key" json:"id"`
+	SeriesID  int `json:"series_id" gorm:"index"`
+	ArticleID int `json:"article_id" gorm:"unique_index"`
+	Position  int `json:"position"`
+}
+
+// ExistSeriesByID checks if a series exists based on ID
+func ExistSeriesByID(id int) (bool, error) {
+	var series Series
+	err := db.Select("id").Where("id = ? AND deleted_on = ? ", id, 0).First(&series).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+
+	return series.ID > 0, nil
+}
+
+// ExistSeriesByName checks if there is a series with the same name
+func ExistSeriesByName(name string) (bool, error) {
+	var series Series
+	err := db.Select("id").Where("name = ? AND deleted_on = ? ", name, 0).First(&series).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+
+	return series.ID > 0, nil
+}
+
+// GetSeriesTotal counts the total number of series based on the constraint
+func GetSeriesTotal(maps interface{}) (int, error) {
+	var count int
+	if err := db.Model(&Series{}).Where(maps).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetSeriesList gets a list of series based on paging and constraints
+func GetSeriesList(pageNum int, pageSize int, maps interface{}) ([]*Series, error) {
+	var series []*Series
+	query := db.Where(maps)
+	if pageSize > 0 {
+		query = query.Offset(pageNum).Limit(pageSize)
+	}
+
+	err := query.Find(&series).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// GetSeries gets a single series based on ID
+func GetSeries(id int) (*Series, error) {
+	var series Series
+	err := db.Where("id = ? AND deleted_on = ? ", id, 0).First(&series).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+// GetSeriesArticles gets the articles of a series that are not deleted, in the series order
+func GetSeriesArticles(seriesID int) ([]*Article, error) {
+	var articles []*Article
+	articleTable := db.NewScope(&Article{}).QuotedTableName()
+	memberTable := db.NewScope(&SeriesArticle{}).QuotedTableName()
+	err := db.Select(articleTable+".*").
+		Joins(fmt.Sprintf("JOIN %s ON %s.article_id = %s.id", memberTable, memberTable, articleTable)).
+		Where(fmt.Sprintf("%s.series_id = ? AND %s.deleted_on = ?", memberTable, articleTable), seriesID, 0).
+		Order(memberTable + ".position").
+		Find(&articles).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// GetArticleSeriesID gets the series the article belongs to, 0 if it has none
+func GetArticleSeriesID(articleID int) (int, error) {
+	var member SeriesArticle
+	err := db.Where("article_id = ?", articleID).First(&member).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return 0, err
+	}
+
+	return member.SeriesID, nil
+}
+
+// GetSeriesConflicts gets the articles among ids which already belong to another series
+func GetSeriesConflicts(seriesID int, articleIDs []int) ([]int, error) {
+	var ids []int
+	err := db.Model(&SeriesArticle{}).Where("article_id IN (?) AND series_id != ?", articleIDs, seriesID).Pluck("article_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// AddSeries add a series along with its articles in a single transaction
+func AddSeries(data map[string]interface{}, articleIDs []int) error {
+	return transaction(func(tx *gorm.DB) error {
+		series := Series{
+			Name:      data["name"].(string),
+			Desc:      data["desc"].(string),
+			CreatedBy: data["created_by"].(string),
+		}
+		if err := tx.Create(&series).Error; err != nil {
+			return err
+		}
+
+		return setSeriesArticles(tx, series.ID, articleIDs)
+	})
+}
+
+// EditSeries modify a single series
+func EditSeries(id int, data interface{}) error {
+	return db.Model(&Series{}).Where("id = ? AND deleted_on = ? ", id, 0).Updates(data).Error
+}
+
+// EditSeriesArticles replaces the articles of a series, their order being the one of articleIDs
+func EditSeriesArticles(id int, articleIDs []int, modifiedBy string) error {
+	return transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Series{}).Where("id = ? AND deleted_on = ? ", id, 0).Update("modified_by", modifiedBy).Error
+		if err != nil {
+			return err
+		}
+
+		return setSeriesArticles(tx, id, articleIDs)
+	})
+}
+
+// DeleteSeries delete a single series, its articles are released
+func DeleteSeries(id int) error {
+	return transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", id).Delete(SeriesArticle{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", id).Delete(Series{}).Error
+	})
+}
+
+func setSeriesArticles(tx *gorm.DB, seriesID int, articleIDs []int) error {
+	if err := tx.Where("series_id = ?", seriesID).Delete(SeriesArticle{}).Error; err != nil {
+		return err
+	}
+
+	for i, articleID := range articleIDs {
+		member := SeriesArticle{
+			SeriesID:  seriesID,
+			ArticleID: articleID,
+			Position:  i + 1,
+		}
+		if err := tx.Create(&member).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
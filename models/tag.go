@@ -1,48 +1,110 @@
 package models
 
 import (
+	"fmt"
+
 	"github.com/jinzhu/gorm"
 )
 
 type Tag struct {
 	Model
 
-	Name       string `json:"name"`
-	CreatedBy  string `json:"created_by"`
-	ModifiedBy string `json:"modified_by"`
-	State      int    `json:"state"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Color         string `json:"color"`
+	CoverImageUrl string `json:"cover_image_url"`
+	SortOrder     int    `json:"sort_order"`
+	CreatedBy     string `json:"created_by"`
+	ModifiedBy    string `json:"modified_by"`
+	State         int    `json:"state"`
 }
 
-// ExistTagByName checks if there is a tag with the same name
+// ExistTagByName checks if there is a tag with the same name or alias
 func ExistTagByName(name string) (bool, error) {
-	var tag Tag
-	err := db.Select("id").Where("name = ? AND deleted_on = ? ", name, 0).First(&tag).Error
-	if err != nil && err != gorm.ErrRecordNotFound {
+	tag, err := GetTagByName(name)
+	if err != nil {
 		return false, err
 	}
 
+	return tag.ID > 0, nil
+}
+
+// GetTagByName gets a single tag based on its name, or the canonical tag the name is an alias of
+func GetTagByName(name string) (*Tag, error) {
+	var tag Tag
+	err := db.Where("name = ? AND deleted_on = ? ", name, 0).First(&tag).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
 	if tag.ID > 0 {
-		return true, nil
+		return &tag, nil
 	}
 
-	return false, nil
+	return getTagByAlias(name)
 }
 
-// AddTag Add a Tag
-func AddTag(name string, state int, createdBy string) error {
-	tag := Tag{
-		Name:      name,
-		State:     state,
-		CreatedBy: createdBy,
+// AddTag Add a Tag and returns its ID, a name that is an alias resolves to the canonical tag instead
+func AddTag(data map[string]interface{}) (int, error) {
+	canonical, err := getTagByAlias(data["name"].(string))
+	if err != nil {
+		return 0, err
 	}
-	if err := db.Create(&tag).Error; err != nil {
-		return err
+	if canonical.ID > 0 {
+		return canonical.ID, nil
 	}
 
-	return nil
+	tag := newTag(data)
+	if err := db.Create(tag).Error; err != nil {
+		return 0, err
+	}
+
+	return tag.ID, nil
+}
+
+// AddTags add the tags in a single transaction and returns their IDs
+func AddTags(data []map[string]interface{}) ([]int, error) {
+	ids := make([]int, 0, len(data))
+	err := transaction(func(tx *gorm.DB) error {
+		for _, v := range data {
+			tag := newTag(v)
+			if err := tx.Create(tag).Error; err != nil {
+				return err
+			}
+			ids = append(ids, tag.ID)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func newTag(data map[string]interface{}) *Tag {
+	tag := &Tag{
+		Name:      data["name"].(string),
+		State:     data["state"].(int),
+		CreatedBy: data["created_by"].(string),
+	}
+	if description, ok := data["description"].(string); ok {
+		tag.Description = description
+	}
+	if color, ok := data["color"].(string); ok {
+		tag.Color = color
+	}
+	if coverImageUrl, ok := data["cover_image_url"].(string); ok {
+		tag.CoverImageUrl = coverImageUrl
+	}
+	if sortOrder, ok := data["sort_order"].(int); ok {
+		tag.SortOrder = sortOrder
+	}
+
+	return tag
 }
 
-// GetTags gets a list of tags based on paging and constraints
+// GetTags gets a list of tags based on paging and constraints, in their sort order
 func GetTags(pageNum int, pageSize int, maps interface{}) ([]Tag, error) {
 	var (
 		tags []Tag
@@ -50,9 +112,9 @@ func GetTags(pageNum int, pageSize int, maps interface{}) ([]Tag, error) {
 	)
 
 	if pageSize > 0 && pageNum > 0 {
-		err = db.Where(maps).Find(&tags).Offset(pageNum).Limit(pageSize).Error
+		err = db.Where(maps).Order("sort_order, id").Find(&tags).Offset(pageNum).Limit(pageSize).Error
 	} else {
-		err = db.Where(maps).Find(&tags).Error
+		err = db.Where(maps).Order("sort_order, id").Find(&tags).Error
 	}
 
 	if err != nil && err != gorm.ErrRecordNotFound {
@@ -95,6 +157,54 @@ func DeleteTag(id int) error {
 	return nil
 }
 
+// DeleteTagIfUnused delete a tag unless articles, deleted ones included, still refer to it, it returns how many do
+func DeleteTagIfUnused(id int) (int, error) {
+	var count int
+	err := transaction(func(tx *gorm.DB) error {
+		// the articles are checked by the statement deleting the tag, so none can be tagged in between
+		res := tx.Where(fmt.Sprintf("id = ? AND NOT EXISTS (SELECT 1 FROM %s WHERE tag_id = ?)",
+			tx.NewScope(&Article{}).QuotedTableName()), id, id).Delete(&Tag{})
+		if res.Error != nil || res.RowsAffected > 0 {
+			return res.Error
+		}
+
+		return tx.Model(&Article{}).Where("tag_id = ?", id).Count(&count).Error
+	})
+
+	return count, err
+}
+
+// DeleteTagMovingArticles delete a tag in a single transaction once its articles, deleted ones included, are moved
+// to the tag toID, or untagged when it is 0; it returns how many were moved, and gorm.ErrRecordNotFound when
+// the tag toID does not exist
+func DeleteTagMovingArticles(id, toID int, modifiedBy string) (int, error) {
+	var moved int
+	err := transaction(func(tx *gorm.DB) error {
+		if toID > 0 {
+			// the tag toID is locked so that it is not deleted before the articles are moved to it
+			var to Tag
+			err := tx.Set("gorm:query_option", "FOR UPDATE").Select("id").
+				Where("id = ? AND deleted_on = ?", toID, 0).First(&to).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		res := tx.Model(&Article{}).Where("tag_id = ?", id).UpdateColumns(withNextVersion(map[string]interface{}{
+			"tag_id":      toID,
+			"modified_by": modifiedBy,
+		}))
+		if res.Error != nil {
+			return res.Error
+		}
+		moved = int(res.RowsAffected)
+
+		return tx.Where("id = ?", id).Delete(&Tag{}).Error
+	})
+
+	return moved, err
+}
+
 // EditTag modify a single tag
 func EditTag(id int, data interface{}) error {
 	if err := db.Model(&Tag{}).Where("id = ? AND deleted_on = ? ", id, 0).Updates(data).Error; err != nil {
@@ -104,6 +214,58 @@ func EditTag(id int, data interface{}) error {
 	return nil
 }
 
+// BatchEditTags modify the tags in a single transaction
+func BatchEditTags(ids []int, data interface{}) ([]*BatchResult, error) {
+	return batch(&Tag{}, ids, false, func(tx *gorm.DB, ids []int) error {
+		return tx.Model(&Tag{}).Where("id IN (?)", ids).Updates(data).Error
+	})
+}
+
+// BatchDeleteTags delete in a single transaction the tags no article, deleted ones included, refers to,
+// the others are reported in use
+func BatchDeleteTags(ids []int) ([]*BatchResult, error) {
+	inUse := make(map[int]bool)
+	results, err := batch(&Tag{}, ids, false, func(tx *gorm.DB, ids []int) error {
+		var used []int
+		if err := tx.Model(&Article{}).Where("tag_id IN (?)", ids).Pluck("DISTINCT tag_id", &used).Error; err != nil {
+			return err
+		}
+		for _, id := range used {
+			inUse[id] = true
+		}
+
+		var unused []int
+		for _, id := range ids {
+			if !inUse[id] {
+				unused = append(unused, id)
+			}
+		}
+		if len(unused) == 0 {
+			return nil
+		}
+
+		return tx.Where("id IN (?)", unused).Delete(Tag{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		if inUse[result.ID] {
+			result.Result = BATCH_IN_USE
+		}
+	}
+
+	return results, nil
+}
+
+// BatchRestoreTags restore the deleted tags in a single transaction
+func BatchRestoreTags(ids []int) ([]*BatchResult, error) {
+	return batch(&Tag{}, ids, true, func(tx *gorm.DB, ids []int) error {
+		return tx.Model(&Tag{}).Where("id IN (?)", ids).Update("deleted_on", 0).Error
+	})
+}
+
 // CleanAllTag clear all tag
 func CleanAllTag() (bool, error) {
 	if err := db.Unscoped().Where("deleted_on != ? ", 0).Delete(&Tag{}).Error; err != nil {
@@ -112,3 +274,22 @@ func CleanAllTag() (bool, error) {
 
 	return true, nil
 }
+
+type TagStat struct {
+	TagID      int `json:"tag_id"`
+	Count      int `json:"count"`
+	LastUsedOn int `json:"last_used_on"`
+}
+
+// GetTagStats counts the articles matching the constraints of each tag in a single grouped query,
+// along with the creation time of the latest one
+func GetTagStats(maps interface{}, conds ...*Cond) ([]*TagStat, error) {
+	var stats []*TagStat
+	err := where(db.Model(&Article{}).Select("tag_id, COUNT(*) AS count, MAX(created_on) AS last_used_on"), maps, conds).
+		Group("tag_id").Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
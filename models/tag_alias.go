@@ -0,0 +1,123 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+// TagAlias is another name of a tag, such as the name of a tag merged into it
+type TagAlias struct {
+	ID        int    `gorm:"primary_key" json:"id"`
+	Name      string `json:"name" gorm:"unique_index"`
+	TagID     int    `json:"tag_id" gorm:"index"`
+	CreatedOn int    `json:"created_on"`
+	CreatedBy string `json:"created_by"`
+}
+
+// GetTagAliases gets the aliases of a tag
+func GetTagAliases(tagID int) ([]*TagAlias, error) {
+	var aliases []*TagAlias
+	err := db.Where("tag_id = ?", tagID).Order("name").Find(&aliases).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return aliases, nil
+}
+
+// GetTagAlias gets a single alias based on its name, its ID is 0 if there is none
+func GetTagAlias(name string) (*TagAlias, error) {
+	var alias TagAlias
+	err := db.Where("name = ?", name).First(&alias).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &alias, nil
+}
+
+// AddTagAlias add a single alias to a tag
+func AddTagAlias(tagID int, name, createdBy string) error {
+	return db.Create(&TagAlias{Name: name, TagID: tagID, CreatedBy: createdBy}).Error
+}
+
+// DeleteTagAlias delete an alias of a tag, it reports whether the tag had it
+func DeleteTagAlias(tagID int, name string) (bool, error) {
+	res := db.Where("tag_id = ? AND name = ?", tagID, name).Delete(&TagAlias{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// MergeTags moves the articles of the source tags to the target and soft deletes the sources in a single
+// transaction, the names and aliases of the sources become aliases of the target; it returns the number
+// of articles moved, and gorm.ErrRecordNotFound when the target does not exist
+func MergeTags(targetID int, sourceIDs []int, modifiedBy string) (int, error) {
+	var moved int
+	err := transaction(func(tx *gorm.DB) error {
+		// the target is locked so that it is not deleted before the articles are moved to it
+		var target Tag
+		err := tx.Set("gorm:query_option", "FOR UPDATE").Where("id = ? AND deleted_on = ?", targetID, 0).First(&target).Error
+		if err != nil {
+			return err
+		}
+
+		var names []string
+		err = tx.Model(&Tag{}).Where("id IN (?) AND deleted_on = ?", sourceIDs, 0).Pluck("name", &names).Error
+		if err != nil {
+			return err
+		}
+
+		// deleted articles move as well, so that restoring them does not bring back a merged tag
+		res := tx.Model(&Article{}).Where("tag_id IN (?)", sourceIDs).UpdateColumns(withNextVersion(map[string]interface{}{
+			"tag_id":      targetID,
+			"modified_by": modifiedBy,
+		}))
+		if res.Error != nil {
+			return res.Error
+		}
+		moved = int(res.RowsAffected)
+
+		if err := tx.Model(&TagAlias{}).Where("tag_id IN (?)", sourceIDs).Update("tag_id", targetID).Error; err != nil {
+			return err
+		}
+		for _, name := range names {
+			if strings.EqualFold(name, target.Name) {
+				continue
+			}
+			if err := tx.Where("name = ?", name).Delete(&TagAlias{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&TagAlias{Name: name, TagID: targetID, CreatedBy: modifiedBy}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&Tag{}).Where("id = ?", targetID).Update("modified_by", modifiedBy).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id IN (?)", sourceIDs).Delete(Tag{}).Error
+	})
+
+	return moved, err
+}
+
+// getTagByAlias gets the tag the name is an alias of, its ID is 0 if there is none
+func getTagByAlias(name string) (*Tag, error) {
+	var tag Tag
+	alias, err := GetTagAlias(name)
+	if err != nil || alias.ID == 0 {
+		return &tag, err
+	}
+
+	err = db.Where("id = ? AND deleted_on = ? ", alias.TagID, 0).First(&tag).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &tag, nil
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+)
+
+type Upload struct {
+	Model
+
+	Name      string `json:"name" gorm:"unique_index"`
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	CreatedBy string `json:"created_by"`
+}
+
+// UploadRef records that the uploaded file is referenced by an entity, such as an article
+type UploadRef struct {
+	ID      int    `gorm:"primary_key" json:"id"`
+	Name    string `json:"name" gorm:"index"`
+	RefType string `json:"ref_type" gorm:"index:idx_blog_upload_ref_ref"`
+	RefID   int    `json:"ref_id" gorm:"index:idx_blog_upload_ref_ref"`
+}
+
+// AddUpload records an uploaded file, a file saved again under the same name is refreshed instead
+func AddUpload(data map[string]interface{}) error {
+	var upload Upload
+	err := db.Where("name = ?", data["name"]).First(&upload).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	if upload.ID > 0 {
+		return db.Model(&upload).Updates(map[string]interface{}{
+			"path":       data["path"],
+			"size":       data["size"],
+			"created_by": data["created_by"],
+		}).Error
+	}
+
+	upload = Upload{
+		Name:      data["name"].(string),
+		Path:      data["path"].(string),
+		Size:      data["size"].(int64),
+		CreatedBy: data["created_by"].(string),
+	}
+	if createdOn, ok := data["created_on"].(int); ok {
+		upload.CreatedOn = createdOn
+		upload.ModifiedOn = createdOn
+	}
+
+	return db.Create(&upload).Error
+}
+
+// GetUploadNames gets the names of the files recorded under the save path
+func GetUploadNames(path string) ([]string, error) {
+	var names []string
+	if err := db.Model(&Upload{}).Where("path = ?", path).Pluck("name", &names).Error; err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// GetUnreferencedUploads gets the files last uploaded before the given time that nothing references
+func GetUnreferencedUploads(before int) ([]*Upload, error) {
+	var uploads []*Upload
+	uploadTable := db.NewScope(&Upload{}).QuotedTableName()
+	refTable := db.NewScope(&UploadRef{}).QuotedTableName()
+	err := db.Where(fmt.Sprintf("modified_on < ? AND NOT EXISTS (SELECT 1 FROM %s WHERE %s.name = %s.name)",
+		refTable, refTable, uploadTable), before).Find(&uploads).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return uploads, nil
+}
+
+// DeleteUpload removes the record of a file
+func DeleteUpload(id int) error {
+	return db.Unscoped().Where("id = ?", id).Delete(&Upload{}).Error
+}
+
+// SetUploadRefs replaces the files referenced by an entity
+func SetUploadRefs(refType string, refID int, names []string) error {
+	return transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("ref_type = ? AND ref_id = ?", refType, refID).Delete(UploadRef{}).Error; err != nil {
+			return err
+		}
+
+		return addUploadRefs(tx, refType, refID, names)
+	})
+}
+
+// ReplaceUploadRefs replaces the files referenced by every entity of a type, entities left out no longer reference any
+func ReplaceUploadRefs(refType string, refs map[int][]string) error {
+	return transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("ref_type = ?", refType).Delete(UploadRef{}).Error; err != nil {
+			return err
+		}
+
+		for refID, names := range refs {
+			if err := addUploadRefs(tx, refType, refID, names); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func addUploadRefs(tx *gorm.DB, refType string, refID int, names []string) error {
+	for _, name := range names {
+		ref := UploadRef{
+			Name:    name,
+			RefType: refType,
+			RefID:   refID,
+		}
+		if err := tx.Create(&ref).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
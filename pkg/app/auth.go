@@ -0,0 +1,21 @@
+package app
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+)
+
+// CLAIMS_KEY is the context key under which the jwt middleware stores the token claims
+const CLAIMS_KEY = "claims"
+
+// GetClaims get the claims of the token that authenticated the request, nil without one
+func GetClaims(c *gin.Context) *util.Claims {
+	if v, ok := c.Get(CLAIMS_KEY); ok {
+		if claims, ok := v.(*util.Claims); ok {
+			return claims
+		}
+	}
+
+	return nil
+}
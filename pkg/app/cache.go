@@ -0,0 +1,48 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CheckNotModified sets the validators of the response and answers 304 when the client copy is still fresh
+func (g *Gin) CheckNotModified(etag string, lastModified time.Time) bool {
+	g.C.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		g.C.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if match := g.C.GetHeader("If-None-Match"); match != "" {
+		if !MatchETag(match, etag) {
+			return false
+		}
+
+		g.C.AbortWithStatus(http.StatusNotModified)
+		return true
+	}
+
+	if since := g.C.GetHeader("If-Modified-Since"); since != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(since)
+		if err != nil || lastModified.Truncate(time.Second).After(t) {
+			return false
+		}
+
+		g.C.AbortWithStatus(http.StatusNotModified)
+		return true
+	}
+
+	return false
+}
+
+// MatchETag checks if the etag is listed in an If-Match or If-None-Match header
+func MatchETag(header, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == "*" || v == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
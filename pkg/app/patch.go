@@ -0,0 +1,62 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"sort"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/gin-gonic/gin"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+)
+
+const MERGE_PATCH_CONTENT_TYPE = "application/merge-patch+json"
+
+// BindMergePatch binds a JSON Merge Patch document into form, validates it
+// and returns the names of the fields present in the document
+func BindMergePatch(c *gin.Context, form interface{}) ([]string, int, int) {
+	contentType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if contentType != MERGE_PATCH_CONTENT_TYPE && contentType != gin.MIMEJSON {
+		return nil, http.StatusUnsupportedMediaType, e.INVALID_PARAMS
+	}
+
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, http.StatusBadRequest, e.INVALID_PARAMS
+	}
+
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(body, &patch); err != nil || patch == nil {
+		return nil, http.StatusBadRequest, e.INVALID_PARAMS
+	}
+
+	fields := make([]string, 0, len(patch))
+	for field, value := range patch {
+		// every column is NOT NULL, so removing a member is not supported
+		if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+			return nil, http.StatusBadRequest, e.INVALID_PARAMS
+		}
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	if err := json.Unmarshal(body, form); err != nil {
+		return nil, http.StatusBadRequest, e.INVALID_PARAMS
+	}
+
+	valid := validation.Validation{}
+	check, err := valid.Valid(form)
+	if err != nil {
+		return nil, http.StatusInternalServerError, e.ERROR
+	}
+	if !check {
+		MarkErrors(valid.Errors)
+		return nil, http.StatusBadRequest, e.INVALID_PARAMS
+	}
+
+	return fields, http.StatusOK, e.SUCCESS
+}
@@ -3,4 +3,8 @@ package e
 const (
 	CACHE_ARTICLE = "ARTICLE"
 	CACHE_TAG     = "TAG"
+
+	CACHE_TAG_STATS = "TAG_STATS"
+
+	CACHE_EXPORT_JOB = "EXPORT_JOB"
 )
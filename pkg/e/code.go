@@ -16,15 +16,81 @@ const (
 	ERROR_EXPORT_TAG_FAIL = 10009
 	ERROR_IMPORT_TAG_FAIL = 10010
 
-	ERROR_NOT_EXIST_ARTICLE        = 10011
-	ERROR_CHECK_EXIST_ARTICLE_FAIL = 10012
-	ERROR_ADD_ARTICLE_FAIL         = 10013
-	ERROR_DELETE_ARTICLE_FAIL      = 10014
-	ERROR_EDIT_ARTICLE_FAIL        = 10015
-	ERROR_COUNT_ARTICLE_FAIL       = 10016
-	ERROR_GET_ARTICLES_FAIL        = 10017
-	ERROR_GET_ARTICLE_FAIL         = 10018
-	ERROR_GEN_ARTICLE_POSTER_FAIL  = 10019
+	ERROR_NOT_EXIST_ARTICLE           = 10011
+	ERROR_CHECK_EXIST_ARTICLE_FAIL    = 10012
+	ERROR_ADD_ARTICLE_FAIL            = 10013
+	ERROR_DELETE_ARTICLE_FAIL         = 10014
+	ERROR_EDIT_ARTICLE_FAIL           = 10015
+	ERROR_COUNT_ARTICLE_FAIL          = 10016
+	ERROR_GET_ARTICLES_FAIL           = 10017
+	ERROR_GET_ARTICLE_FAIL            = 10018
+	ERROR_GEN_ARTICLE_POSTER_FAIL     = 10019
+	ERROR_GET_FEED_FAIL               = 10020
+	ERROR_GEN_SITEMAP_FAIL            = 10021
+	ERROR_BATCH_ARTICLE_FAIL          = 10022
+	ERROR_BATCH_TAG_FAIL              = 10023
+	ERROR_ARTICLE_PRECONDITION_FAILED = 10024
+	ERROR_EXIST_ARTICLE_SLUG          = 10025
+	ERROR_EXPORT_ARTICLE_FAIL         = 10026
+	ERROR_IMPORT_ARTICLE_FAIL         = 10027
+
+	ERROR_NOT_EXIST_SERIES         = 10028
+	ERROR_EXIST_SERIES             = 10029
+	ERROR_CHECK_EXIST_SERIES_FAIL  = 10030
+	ERROR_GET_SERIES_FAIL          = 10031
+	ERROR_COUNT_SERIES_FAIL        = 10032
+	ERROR_ADD_SERIES_FAIL          = 10033
+	ERROR_EDIT_SERIES_FAIL         = 10034
+	ERROR_DELETE_SERIES_FAIL       = 10035
+	ERROR_NOT_EXIST_SERIES_ARTICLE = 10036
+	ERROR_SERIES_ARTICLE_IN_OTHER  = 10037
+
+	ERROR_ARTICLE_PASSWORD_NOT_SET  = 10038
+	ERROR_ARTICLE_PASSWORD_REQUIRED = 10039
+	ERROR_ARTICLE_PASSWORD_WRONG    = 10040
+	ERROR_PREVIEW_INVALID           = 10041
+	ERROR_SIGN_PREVIEW_FAIL         = 10042
+
+	ERROR_GET_TRANSLATIONS_FAIL   = 10043
+	ERROR_SAVE_TRANSLATION_FAIL   = 10044
+	ERROR_DELETE_TRANSLATION_FAIL = 10045
+	ERROR_NOT_EXIST_TRANSLATION   = 10046
+	ERROR_TRANSLATION_SOURCE_LANG = 10047
+
+	ERROR_NOT_EXIST_AUTHOR        = 10048
+	ERROR_EXIST_AUTHOR            = 10049
+	ERROR_CHECK_EXIST_AUTHOR_FAIL = 10050
+	ERROR_GET_AUTHOR_FAIL         = 10051
+	ERROR_ADD_AUTHOR_FAIL         = 10052
+	ERROR_EDIT_AUTHOR_FAIL        = 10053
+	ERROR_NOT_EXIST_AUTH          = 10054
+	ERROR_AUTHOR_AVATAR_INVALID   = 10055
+
+	ERROR_GET_ARTICLE_ARCHIVE_FAIL = 10056
+
+	ERROR_MERGE_TAG_FAIL        = 10057
+	ERROR_NOT_EXIST_MERGE_TAG   = 10058
+	ERROR_MERGE_TAG_INTO_ITSELF = 10059
+	ERROR_GET_TAG_ALIASES_FAIL  = 10060
+	ERROR_ADD_TAG_ALIAS_FAIL    = 10061
+	ERROR_DELETE_TAG_ALIAS_FAIL = 10062
+	ERROR_EXIST_TAG_ALIAS       = 10063
+	ERROR_NOT_EXIST_TAG_ALIAS   = 10064
+	ERROR_IMPORT_TAG_NO_NAME    = 10065
+	ERROR_START_EXPORT_FAIL     = 10066
+	ERROR_GET_EXPORT_FAIL       = 10067
+	ERROR_NOT_EXIST_EXPORT      = 10068
+	ERROR_GET_TAG_STATS_FAIL    = 10069
+
+	ERROR_TAG_IN_USE             = 10070
+	ERROR_NOT_EXIST_REASSIGN_TAG = 10071
+	ERROR_REASSIGN_TAG_TO_ITSELF = 10072
+	ERROR_TAG_COVER_INVALID      = 10073
+
+	ERROR_NOT_EXIST_POSTER_TEMPLATE = 10074
+	ERROR_GET_POSTER_TEMPLATES_FAIL = 10075
+
+	ERROR_IMPORT_DUPLICATE_SLUG = 10076
 
 	ERROR_AUTH_CHECK_TOKEN_FAIL    = 20001
 	ERROR_AUTH_CHECK_TOKEN_TIMEOUT = 20002
@@ -34,4 +100,5 @@ const (
 	ERROR_UPLOAD_SAVE_IMAGE_FAIL    = 30001
 	ERROR_UPLOAD_CHECK_IMAGE_FAIL   = 30002
 	ERROR_UPLOAD_CHECK_IMAGE_FORMAT = 30003
+	ERROR_UPLOAD_CLEAN_FAIL         = 30004
 )
@@ -1,35 +1,93 @@
 package e
 
 var MsgFlags = map[int]string{
-	SUCCESS:                         "ok",
-	ERROR:                           "fail",
-	INVALID_PARAMS:                  "请求参数错误",
-	ERROR_EXIST_TAG:                 "已存在该标签名称",
-	ERROR_EXIST_TAG_FAIL:            "获取已存在标签失败",
-	ERROR_NOT_EXIST_TAG:             "该标签不存在",
-	ERROR_GET_TAGS_FAIL:             "获取所有标签失败",
-	ERROR_COUNT_TAG_FAIL:            "统计标签失败",
-	ERROR_ADD_TAG_FAIL:              "新增标签失败",
-	ERROR_EDIT_TAG_FAIL:             "修改标签失败",
-	ERROR_DELETE_TAG_FAIL:           "删除标签失败",
-	ERROR_EXPORT_TAG_FAIL:           "导出标签失败",
-	ERROR_IMPORT_TAG_FAIL:           "导入标签失败",
-	ERROR_NOT_EXIST_ARTICLE:         "该文章不存在",
-	ERROR_ADD_ARTICLE_FAIL:          "新增文章失败",
-	ERROR_DELETE_ARTICLE_FAIL:       "删除文章失败",
-	ERROR_CHECK_EXIST_ARTICLE_FAIL:  "检查文章是否存在失败",
-	ERROR_EDIT_ARTICLE_FAIL:         "修改文章失败",
-	ERROR_COUNT_ARTICLE_FAIL:        "统计文章失败",
-	ERROR_GET_ARTICLES_FAIL:         "获取多个文章失败",
-	ERROR_GET_ARTICLE_FAIL:          "获取单个文章失败",
-	ERROR_GEN_ARTICLE_POSTER_FAIL:   "生成文章海报失败",
-	ERROR_AUTH_CHECK_TOKEN_FAIL:     "Token鉴权失败",
-	ERROR_AUTH_CHECK_TOKEN_TIMEOUT:  "Token已超时",
-	ERROR_AUTH_TOKEN:                "Token生成失败",
-	ERROR_AUTH:                      "Token错误",
-	ERROR_UPLOAD_SAVE_IMAGE_FAIL:    "保存图片失败",
-	ERROR_UPLOAD_CHECK_IMAGE_FAIL:   "检查图片失败",
-	ERROR_UPLOAD_CHECK_IMAGE_FORMAT: "校验图片错误，图片格式或大小有问题",
+	SUCCESS:                           "ok",
+	ERROR:                             "fail",
+	INVALID_PARAMS:                    "请求参数错误",
+	ERROR_EXIST_TAG:                   "已存在该标签名称",
+	ERROR_EXIST_TAG_FAIL:              "获取已存在标签失败",
+	ERROR_NOT_EXIST_TAG:               "该标签不存在",
+	ERROR_GET_TAGS_FAIL:               "获取所有标签失败",
+	ERROR_COUNT_TAG_FAIL:              "统计标签失败",
+	ERROR_ADD_TAG_FAIL:                "新增标签失败",
+	ERROR_EDIT_TAG_FAIL:               "修改标签失败",
+	ERROR_DELETE_TAG_FAIL:             "删除标签失败",
+	ERROR_EXPORT_TAG_FAIL:             "导出标签失败",
+	ERROR_IMPORT_TAG_FAIL:             "导入标签失败",
+	ERROR_NOT_EXIST_ARTICLE:           "该文章不存在",
+	ERROR_ADD_ARTICLE_FAIL:            "新增文章失败",
+	ERROR_DELETE_ARTICLE_FAIL:         "删除文章失败",
+	ERROR_CHECK_EXIST_ARTICLE_FAIL:    "检查文章是否存在失败",
+	ERROR_EDIT_ARTICLE_FAIL:           "修改文章失败",
+	ERROR_COUNT_ARTICLE_FAIL:          "统计文章失败",
+	ERROR_GET_ARTICLES_FAIL:           "获取多个文章失败",
+	ERROR_GET_ARTICLE_FAIL:            "获取单个文章失败",
+	ERROR_GEN_ARTICLE_POSTER_FAIL:     "生成文章海报失败",
+	ERROR_GET_FEED_FAIL:               "生成订阅源失败",
+	ERROR_GEN_SITEMAP_FAIL:            "生成站点地图失败",
+	ERROR_BATCH_ARTICLE_FAIL:          "批量操作文章失败",
+	ERROR_BATCH_TAG_FAIL:              "批量操作标签失败",
+	ERROR_ARTICLE_PRECONDITION_FAILED: "文章已被他人修改，请刷新后重试",
+	ERROR_EXIST_ARTICLE_SLUG:          "已存在该文章别名",
+	ERROR_EXPORT_ARTICLE_FAIL:         "导出文章失败",
+	ERROR_IMPORT_ARTICLE_FAIL:         "导入文章失败",
+	ERROR_NOT_EXIST_SERIES:            "该系列不存在",
+	ERROR_EXIST_SERIES:                "已存在该系列名称",
+	ERROR_CHECK_EXIST_SERIES_FAIL:     "检查系列是否存在失败",
+	ERROR_GET_SERIES_FAIL:             "获取系列失败",
+	ERROR_COUNT_SERIES_FAIL:           "统计系列失败",
+	ERROR_ADD_SERIES_FAIL:             "新增系列失败",
+	ERROR_EDIT_SERIES_FAIL:            "修改系列失败",
+	ERROR_DELETE_SERIES_FAIL:          "删除系列失败",
+	ERROR_NOT_EXIST_SERIES_ARTICLE:    "系列中的文章不存在",
+	ERROR_SERIES_ARTICLE_IN_OTHER:     "文章已属于其他系列",
+	ERROR_ARTICLE_PASSWORD_NOT_SET:    "密码保护的文章必须设置密码",
+	ERROR_ARTICLE_PASSWORD_REQUIRED:   "该文章需要密码才能访问",
+	ERROR_ARTICLE_PASSWORD_WRONG:      "文章密码错误",
+	ERROR_PREVIEW_INVALID:             "预览链接无效或已过期",
+	ERROR_SIGN_PREVIEW_FAIL:           "生成预览链接失败",
+	ERROR_GET_TRANSLATIONS_FAIL:       "获取文章译文失败",
+	ERROR_SAVE_TRANSLATION_FAIL:       "保存文章译文失败",
+	ERROR_DELETE_TRANSLATION_FAIL:     "删除文章译文失败",
+	ERROR_NOT_EXIST_TRANSLATION:       "该文章没有此语言的译文",
+	ERROR_TRANSLATION_SOURCE_LANG:     "译文语言不能与文章原文语言相同",
+	ERROR_NOT_EXIST_AUTHOR:            "该作者不存在",
+	ERROR_EXIST_AUTHOR:                "该账号已有作者资料",
+	ERROR_CHECK_EXIST_AUTHOR_FAIL:     "检查作者是否存在失败",
+	ERROR_GET_AUTHOR_FAIL:             "获取作者失败",
+	ERROR_ADD_AUTHOR_FAIL:             "新增作者失败",
+	ERROR_EDIT_AUTHOR_FAIL:            "修改作者失败",
+	ERROR_NOT_EXIST_AUTH:              "该账号不存在",
+	ERROR_AUTHOR_AVATAR_INVALID:       "头像必须是已上传的图片",
+	ERROR_GET_ARTICLE_ARCHIVE_FAIL:    "获取文章归档失败",
+	ERROR_MERGE_TAG_FAIL:              "合并标签失败",
+	ERROR_NOT_EXIST_MERGE_TAG:         "要合并的标签不存在",
+	ERROR_MERGE_TAG_INTO_ITSELF:       "不能将标签合并到自身",
+	ERROR_GET_TAG_ALIASES_FAIL:        "获取标签别名失败",
+	ERROR_ADD_TAG_ALIAS_FAIL:          "新增标签别名失败",
+	ERROR_DELETE_TAG_ALIAS_FAIL:       "删除标签别名失败",
+	ERROR_EXIST_TAG_ALIAS:             "该名称已被标签或别名使用",
+	ERROR_NOT_EXIST_TAG_ALIAS:         "该标签没有此别名",
+	ERROR_IMPORT_TAG_NO_NAME:          "导入文件缺少名称列",
+	ERROR_START_EXPORT_FAIL:           "创建导出任务失败",
+	ERROR_GET_EXPORT_FAIL:             "获取导出任务失败",
+	ERROR_NOT_EXIST_EXPORT:            "导出任务不存在或已过期",
+	ERROR_GET_TAG_STATS_FAIL:          "获取标签统计失败",
+	ERROR_TAG_IN_USE:                  "该标签仍被文章使用",
+	ERROR_NOT_EXIST_REASSIGN_TAG:      "要转移到的标签不存在",
+	ERROR_REASSIGN_TAG_TO_ITSELF:      "不能将文章转移到被删除的标签",
+	ERROR_TAG_COVER_INVALID:           "封面必须是已上传的图片",
+	ERROR_NOT_EXIST_POSTER_TEMPLATE:   "海报模板不存在",
+	ERROR_GET_POSTER_TEMPLATES_FAIL:   "获取海报模板失败",
+	ERROR_IMPORT_DUPLICATE_SLUG:       "导入文件中有重复的别名",
+	ERROR_AUTH_CHECK_TOKEN_FAIL:       "Token鉴权失败",
+	ERROR_AUTH_CHECK_TOKEN_TIMEOUT:    "Token已超时",
+	ERROR_AUTH_TOKEN:                  "Token生成失败",
+	ERROR_AUTH:                        "Token错误",
+	ERROR_UPLOAD_SAVE_IMAGE_FAIL:      "保存图片失败",
+	ERROR_UPLOAD_CHECK_IMAGE_FAIL:     "检查图片失败",
+	ERROR_UPLOAD_CHECK_IMAGE_FORMAT:   "校验图片错误，图片格式或大小有问题",
+	ERROR_UPLOAD_CLEAN_FAIL:           "清理未引用的图片失败",
 }
 
 // GetMsg get error information based on Code
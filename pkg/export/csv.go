@@ -0,0 +1,38 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+)
+
+const utf8BOM = "\ufeff"
+
+// Csv is a comma separated file, encoded in UTF-8
+type Csv struct{}
+
+func (*Csv) Ext() string {
+	return ".csv"
+}
+
+func (*Csv) Export(w io.Writer, sheet string, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return writer.Error()
+}
+
+// Import reads the rows, a leading byte order mark as written by Excel is skipped
+func (*Csv) Import(r io.Reader, sheet string) ([][]string, error) {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(len(utf8BOM)); err == nil && string(bom) == utf8BOM {
+		br.Discard(len(utf8BOM))
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+
+	return reader.ReadAll()
+}
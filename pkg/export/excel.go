@@ -1,9 +1,69 @@
 package export
 
-import "github.com/EDDYCJY/go-gin-example/pkg/setting"
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"strconv"
+
+	"github.com/360EntSecGroup-Skylar/excelize"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
 
 const EXT = ".xlsx"
 
+// Xlsx is an Excel workbook holding the table in a single sheet
+type Xlsx struct{}
+
+func (*Xlsx) Ext() string {
+	return EXT
+}
+
+// Export writes the rows with excelize rather than xlsx, whose blank cells are read back as the first shared string
+func (*Xlsx) Export(w io.Writer, sheet string, rows [][]string) error {
+	xlsFile := excelize.NewFile()
+	xlsFile.SetSheetName("Sheet1", sheet)
+	for irow, values := range rows {
+		for i, value := range values {
+			if value != "" {
+				xlsFile.SetCellStr(sheet, excelize.ToAlphaString(i)+strconv.Itoa(irow+1), value)
+			}
+		}
+	}
+
+	return xlsFile.Write(w)
+}
+
+// Import reads the sheet, or the first sheet of a workbook without one of that name
+func (*Xlsx) Import(r io.Reader, sheet string) ([][]string, error) {
+	xlsFile, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if xlsFile.GetSheetIndex(sheet) == 0 {
+		first := 0
+		for index, name := range xlsFile.GetSheetMap() {
+			if first == 0 || index < first {
+				first, sheet = index, name
+			}
+		}
+	}
+
+	return xlsFile.GetRows(sheet), nil
+}
+
+// NewName get a random name for an export file, the files being served to whoever knows their url
+func NewName(prefix string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return prefix + "-" + hex.EncodeToString(b), nil
+}
+
 // GetExcelFullUrl get the full access path of the Excel file
 func GetExcelFullUrl(name string) string {
 	return setting.AppSetting.PrefixUrl + "/" + GetExcelPath() + name
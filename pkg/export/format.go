@@ -0,0 +1,77 @@
+package export
+
+import (
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	FORMAT_XLSX = "xlsx"
+	FORMAT_CSV  = "csv"
+	FORMAT_JSON = "json"
+)
+
+// Exporter writes a table, its first row holding the titles, the sheet being used by the formats that have one
+type Exporter interface {
+	Export(w io.Writer, sheet string, rows [][]string) error
+}
+
+// Importer reads back a table written by an Exporter, its first row holding the titles
+type Importer interface {
+	Import(r io.Reader, sheet string) ([][]string, error)
+}
+
+// Progress is told how many rows of an export are done
+type Progress func(done, total int)
+
+// Report tells the progress, if any
+func (p Progress) Report(done, total int) {
+	if p != nil {
+		p(done, total)
+	}
+}
+
+// Format exports and imports a table in a file format
+type Format interface {
+	Exporter
+	Importer
+
+	Ext() string
+}
+
+var formats = map[string]Format{
+	FORMAT_XLSX: &Xlsx{},
+	FORMAT_CSV:  &Csv{},
+	FORMAT_JSON: &Json{},
+}
+
+var contentTypes = map[string]string{
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": FORMAT_XLSX,
+	"text/csv":         FORMAT_CSV,
+	"application/csv":  FORMAT_CSV,
+	"application/json": FORMAT_JSON,
+	"text/json":        FORMAT_JSON,
+}
+
+// GetFormat get the format by its name
+func GetFormat(name string) (Format, bool) {
+	format, ok := formats[strings.ToLower(name)]
+	return format, ok
+}
+
+// GetFormatByContentType get the format of a media type, its parameters being ignored
+func GetFormatByContentType(contentType string) (Format, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+
+	return GetFormat(contentTypes[mediaType])
+}
+
+// GetFormatByFilename get the format of a file by its extension
+func GetFormatByFilename(name string) (Format, bool) {
+	return GetFormat(strings.TrimPrefix(filepath.Ext(name), "."))
+}
@@ -0,0 +1,96 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Json is an array of objects keyed by the titles
+type Json struct{}
+
+func (*Json) Ext() string {
+	return ".json"
+}
+
+// Export writes an object per row, its keys in the order of the titles
+func (*Json) Export(w io.Writer, sheet string, rows [][]string) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("[")
+	for irow := 1; irow < len(rows); irow++ {
+		if irow > 1 {
+			bw.WriteString(",")
+		}
+		bw.WriteString("\n  {")
+		for i, title := range rows[0] {
+			var value string
+			if i < len(rows[irow]) {
+				value = rows[irow][i]
+			}
+
+			key, _ := json.Marshal(title)
+			cell, _ := json.Marshal(value)
+			if i > 0 {
+				bw.WriteString(", ")
+			}
+			bw.Write(key)
+			bw.WriteString(": ")
+			bw.Write(cell)
+		}
+		bw.WriteString("}")
+	}
+	bw.WriteString("\n]\n")
+
+	return bw.Flush()
+}
+
+// Import reads the objects, the titles being their keys in sorted order; numbers and booleans are
+// kept as written and null is read as an empty cell
+func (*Json) Import(r io.Reader, sheet string) ([][]string, error) {
+	var objects []map[string]interface{}
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&objects); err != nil {
+		return nil, err
+	}
+
+	var titles []string
+	columns := make(map[string]int)
+	for _, object := range objects {
+		for key := range object {
+			if _, ok := columns[key]; !ok {
+				columns[key] = 0
+				titles = append(titles, key)
+			}
+		}
+	}
+	sort.Strings(titles)
+	for i, title := range titles {
+		columns[title] = i
+	}
+
+	rows := make([][]string, 0, len(objects)+1)
+	rows = append(rows, titles)
+	for i, object := range objects {
+		row := make([]string, len(titles))
+		for key, value := range object {
+			switch v := value.(type) {
+			case nil:
+			case string:
+				row[columns[key]] = v
+			case json.Number:
+				row[columns[key]] = v.String()
+			case bool:
+				row[columns[key]] = strconv.FormatBool(v)
+			default:
+				return nil, fmt.Errorf("object %d: %q is not a string, number or boolean", i+1, key)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
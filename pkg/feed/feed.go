@@ -0,0 +1,60 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const (
+	RSS  = "rss"
+	ATOM = "atom"
+
+	RSS_CONTENT_TYPE  = "application/rss+xml; charset=utf-8"
+	ATOM_CONTENT_TYPE = "application/atom+xml; charset=utf-8"
+)
+
+type Feed struct {
+	Title       string
+	Link        string
+	SelfLink    string
+	Description string
+	Author      string
+	Updated     time.Time
+	Items       []*Item
+}
+
+type Item struct {
+	Title       string
+	Link        string
+	Description string
+	Author      string
+	Category    string
+	Created     time.Time
+	Updated     time.Time
+}
+
+// GetContentType get the content type of the feed format
+func GetContentType(format string) string {
+	if format == ATOM {
+		return ATOM_CONTENT_TYPE
+	}
+
+	return RSS_CONTENT_TYPE
+}
+
+// Encode render the feed in the given format
+func (f *Feed) Encode(format string) ([]byte, error) {
+	var v interface{}
+	if format == ATOM {
+		v = f.toAtom()
+	} else {
+		v = f.toRSS()
+	}
+
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,68 @@
+package feed
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+type rss struct {
+	XMLName xml.Name    `xml:"rss"`
+	Version string      `xml:"version,attr"`
+	Atom    string      `xml:"xmlns:atom,attr"`
+	Channel *rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	AtomLink      *atomLink  `xml:"atom:link"`
+	Description   string     `xml:"description"`
+	LastBuildDate string     `xml:"lastBuildDate,omitempty"`
+	Items         []*rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Guid        *rssGuid `xml:"guid"`
+	Description string   `xml:"description"`
+	Author      string   `xml:"author,omitempty"`
+	Category    string   `xml:"category,omitempty"`
+	PubDate     string   `xml:"pubDate"`
+}
+
+type rssGuid struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// toRSS convert the feed into an RSS 2.0 document
+func (f *Feed) toRSS() *rss {
+	channel := &rssChannel{
+		Title:       f.Title,
+		Link:        f.Link,
+		AtomLink:    &atomLink{Href: f.SelfLink, Rel: "self", Type: "application/rss+xml"},
+		Description: f.Description,
+	}
+	if !f.Updated.IsZero() {
+		channel.LastBuildDate = f.Updated.UTC().Format(http.TimeFormat)
+	}
+
+	for _, item := range f.Items {
+		channel.Items = append(channel.Items, &rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Guid:        &rssGuid{IsPermaLink: true, Value: item.Link},
+			Description: item.Description,
+			Author:      item.Author,
+			Category:    item.Category,
+			PubDate:     item.Created.UTC().Format(http.TimeFormat),
+		})
+	}
+
+	return &rss{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: channel,
+	}
+}
@@ -2,6 +2,7 @@ package file
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"mime/multipart"
 	"os"
@@ -90,3 +91,33 @@ func MustOpen(fileName, filePath string) (*os.File, error) {
 
 	return f, nil
 }
+
+// WriteFile writes the file through a temporary one renamed into place, so that readers never see it half written
+func WriteFile(dir, name string, write func(w io.Writer) error) error {
+	if err := IsNotExistMkDir(dir); err != nil {
+		return err
+	}
+
+	// the temporary file is hidden from the globs matching the names of the files written
+	f, err := ioutil.TempFile(dir, "."+name+".tmp")
+	if err != nil {
+		return err
+	}
+
+	if err := write(f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Chmod(0644); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), dir+name)
+}
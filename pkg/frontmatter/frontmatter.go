@@ -0,0 +1,63 @@
+package frontmatter
+
+import (
+	"bytes"
+	"errors"
+
+	"gopkg.in/yaml.v2"
+)
+
+const DELIMITER = "---"
+
+// ErrMissing is returned when a document does not start with a front matter block
+var ErrMissing = errors.New("frontmatter: document has no front matter")
+
+// Marshal renders meta as YAML front matter followed by the body as it is
+func Marshal(meta interface{}, body string) ([]byte, error) {
+	data, err := yaml.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(DELIMITER + "\n")
+	buf.Write(data)
+	buf.WriteString(DELIMITER + "\n")
+	buf.WriteString(body)
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes the YAML front matter of a document into meta and returns the body,
+// everything after the line closing the front matter byte for byte
+func Unmarshal(data []byte, meta interface{}) (string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	line, start := readLine(data, 0)
+	if line != DELIMITER {
+		return "", ErrMissing
+	}
+
+	for pos := start; pos < len(data); {
+		line, next := readLine(data, pos)
+		if line == DELIMITER {
+			if err := yaml.Unmarshal(data[start:pos], meta); err != nil {
+				return "", err
+			}
+
+			return string(data[next:]), nil
+		}
+		pos = next
+	}
+
+	return "", ErrMissing
+}
+
+// readLine reads the line starting at pos without its line ending, and returns where the next one starts
+func readLine(data []byte, pos int) (string, int) {
+	end := bytes.IndexByte(data[pos:], '\n')
+	if end < 0 {
+		return string(bytes.TrimSuffix(data[pos:], []byte("\r"))), len(data)
+	}
+
+	return string(bytes.TrimSuffix(data[pos:pos+end], []byte("\r"))), pos + end + 1
+}
@@ -0,0 +1,307 @@
+package poster
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+// lines of text are this many times their size apart unless the layer says otherwise
+const defaultLineHeight = 1.2
+
+var (
+	fonts     = make(map[string]*truetype.Font)
+	fontMutex sync.Mutex
+)
+
+// Data is what the layers of a template are drawn with
+type Data struct {
+	Vars  map[string]string
+	Cover image.Image
+	Qr    image.Image
+}
+
+func (d *Data) has(name string) bool {
+	switch name {
+	case LAYER_COVER:
+		return d.Cover != nil
+	case LAYER_QR:
+		return d.Qr != nil
+	}
+
+	return d.Vars[name] != ""
+}
+
+// Render draws the layers of the template, skipping the cover and qr ones without an image
+func (t *Template) Render(data *Data) (*image.RGBA, error) {
+	dst := image.NewRGBA(image.Rect(0, 0, t.Width, t.Height))
+	if t.Background != "" {
+		c, _ := parseColor(t.Background)
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	}
+
+	pairs := make([]string, 0, len(data.Vars)*2)
+	for k, v := range data.Vars {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	for _, l := range t.Layers {
+		if l.When != "" && data.has(strings.TrimPrefix(l.When, "!")) == strings.HasPrefix(l.When, "!") {
+			continue
+		}
+
+		var err error
+		switch l.Type {
+		case LAYER_IMAGE:
+			err = l.drawImage(dst)
+		case LAYER_COVER:
+			l.drawFill(dst, data.Cover)
+		case LAYER_QR:
+			l.drawFill(dst, data.Qr)
+		case LAYER_TEXT:
+			err = l.drawText(dst, replacer.Replace(l.Text), t.getFonts(l))
+		case LAYER_RECT:
+			c, _ := parseColor(l.Color)
+			draw.DrawMask(dst, l.rect(), image.NewUniform(c), image.Point{}, l.mask(), l.rect().Min, draw.Over)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return dst, nil
+}
+
+func (l *Layer) rect() image.Rectangle {
+	return image.Rect(l.X, l.Y, l.X+l.Width, l.Y+l.Height)
+}
+
+func (l *Layer) mask() image.Image {
+	return &roundedMask{rect: l.rect(), radius: l.Radius}
+}
+
+func (l *Layer) drawImage(dst draw.Image) error {
+	f, err := os.Open(setting.AppSetting.RuntimeRootPath + l.Src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return fmt.Errorf("poster image %s: %v", l.Src, err)
+	}
+
+	// an image without a size of its own is drawn as it is
+	layer := *l
+	if layer.Width == 0 || layer.Height == 0 {
+		layer.Width, layer.Height = src.Bounds().Dx(), src.Bounds().Dy()
+	}
+	layer.drawFill(dst, src)
+
+	return nil
+}
+
+// drawFill scales the image to fill the box, cropping what overflows around its center
+func (l *Layer) drawFill(dst draw.Image, src image.Image) {
+	if src == nil {
+		return
+	}
+	rect := l.rect()
+	bounds := src.Bounds()
+	if bounds.Empty() || rect.Empty() {
+		return
+	}
+
+	// the scale is the larger of both ratios so that the box is filled
+	scale := float64(rect.Dx()) / float64(bounds.Dx())
+	if s := float64(rect.Dy()) / float64(bounds.Dy()); s > scale {
+		scale = s
+	}
+	offX := (float64(bounds.Dx()) - float64(rect.Dx())/scale) / 2
+	offY := (float64(bounds.Dy()) - float64(rect.Dy())/scale) / 2
+
+	scaled := image.NewRGBA(rect)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		sy := bounds.Min.Y + int(offY+float64(y-rect.Min.Y)/scale)
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			sx := bounds.Min.X + int(offX+float64(x-rect.Min.X)/scale)
+			scaled.Set(x, y, src.At(sx, sy))
+		}
+	}
+
+	draw.DrawMask(dst, rect, scaled, rect.Min, l.mask(), rect.Min, draw.Over)
+}
+
+// drawText draws the text wrapped in the width of the box from its top, the last of MaxLines lines
+// ending with an ellipsis when the text is longer
+func (l *Layer) drawText(dst draw.Image, text string, fontNames []string) error {
+	var fonts []*truetype.Font
+	for i, name := range fontNames {
+		f, err := getFont(name)
+		if err != nil {
+			// a missing fallback only leaves the glyphs it would have drawn to the fonts before it
+			if i == 0 {
+				return err
+			}
+			logging.Warn(err)
+			continue
+		}
+		fonts = append(fonts, f)
+	}
+
+	face := newFallbackFace(fonts, l.Size)
+	defer face.Close()
+
+	c := color.Color(color.Black)
+	if l.Color != "" {
+		c, _ = parseColor(l.Color)
+	}
+	lineHeight := l.LineHeight
+	if lineHeight == 0 {
+		lineHeight = defaultLineHeight
+	}
+
+	lines := wrap(face, text, l.Width)
+	if l.MaxLines > 0 && len(lines) > l.MaxLines {
+		lines = lines[:l.MaxLines]
+		lines[l.MaxLines-1] = ellipsize(face, lines[l.MaxLines-1], l.Width)
+	}
+
+	d := &font.Drawer{Dst: dst, Src: image.NewUniform(c), Face: face}
+	y := fixed.I(l.Y) + face.Metrics().Ascent
+	for _, line := range lines {
+		x := fixed.I(l.X)
+		switch l.Align {
+		case ALIGN_CENTER:
+			x += (fixed.I(l.Width) - d.MeasureString(line)) / 2
+		case ALIGN_RIGHT:
+			x += fixed.I(l.Width) - d.MeasureString(line)
+		}
+
+		d.Dot = fixed.Point26_6{X: x, Y: y}
+		d.DrawString(line)
+		y += fixed.Int26_6(l.Size * lineHeight * 64)
+	}
+
+	return nil
+}
+
+// getFonts gets the fonts a text layer is drawn with, its own or the template one first, then the fallbacks
+func (t *Template) getFonts(l *Layer) []string {
+	primary := l.Font
+	if primary == "" {
+		primary = t.Font
+	}
+
+	names := []string{primary}
+	seen := map[string]bool{primary: true}
+	for _, name := range append(append([]string{}, t.FallbackFonts...), setting.AppSetting.PosterFallbackFonts...) {
+		if name = strings.TrimSpace(name); name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// getFont parses a font of the font path once, later draws sharing it
+func getFont(name string) (*truetype.Font, error) {
+	fontMutex.Lock()
+	defer fontMutex.Unlock()
+
+	if f, ok := fonts[name]; ok {
+		return f, nil
+	}
+
+	data, err := ioutil.ReadFile(setting.AppSetting.RuntimeRootPath + setting.AppSetting.FontSavePath + name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := truetype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("poster font %s: %v", name, err)
+	}
+
+	fonts[name] = f
+	return f, nil
+}
+
+// parseColor parses a #RRGGBB or #RRGGBBAA color
+func parseColor(s string) (color.Color, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 8 || !strings.HasPrefix(s, "#") {
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// roundedMask is opaque inside the rect but outside of its rounded corners
+type roundedMask struct {
+	rect   image.Rectangle
+	radius int
+}
+
+func (m *roundedMask) ColorModel() color.Model {
+	return color.AlphaModel
+}
+
+func (m *roundedMask) Bounds() image.Rectangle {
+	return m.rect
+}
+
+func (m *roundedMask) At(x, y int) color.Color {
+	if !(image.Point{X: x, Y: y}).In(m.rect) {
+		return color.Transparent
+	}
+
+	r := m.radius
+	if max := m.rect.Dx() / 2; r > max {
+		r = max
+	}
+	if max := m.rect.Dy() / 2; r > max {
+		r = max
+	}
+
+	// the distance is taken from the center of the pixel to the center of the corner's circle
+	cx, cy := x, y
+	switch {
+	case x < m.rect.Min.X+r:
+		cx = m.rect.Min.X + r
+	case x >= m.rect.Max.X-r:
+		cx = m.rect.Max.X - r - 1
+	}
+	switch {
+	case y < m.rect.Min.Y+r:
+		cy = m.rect.Min.Y + r
+	case y >= m.rect.Max.Y-r:
+		cy = m.rect.Max.Y - r - 1
+	}
+	if dx, dy := x-cx, y-cy; dx*dx+dy*dy > r*r {
+		return color.Transparent
+	}
+
+	return color.Opaque
+}
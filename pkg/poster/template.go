@@ -0,0 +1,273 @@
+package poster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+const (
+	LAYER_IMAGE = "image"
+	LAYER_COVER = "cover"
+	LAYER_QR    = "qr"
+	LAYER_TEXT  = "text"
+	LAYER_RECT  = "rect"
+
+	ALIGN_LEFT   = "left"
+	ALIGN_CENTER = "center"
+	ALIGN_RIGHT  = "right"
+
+	FORMAT_JPEG = "jpeg"
+	FORMAT_PNG  = "png"
+)
+
+// template names end up in poster file names, so they are kept to letters, digits and underscores
+var nameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// templates are looked up by these extensions, in this order
+var exts = []string{".json", ".yaml", ".yml"}
+
+// Template is a poster made of layers drawn in their order, its name is the one of its file;
+// its FallbackFonts draw the glyphs missing from the font of a text, before the configured ones.
+// It is saved as a JPEG of Quality, 0 for the default one, or as a PNG keeping what is left transparent
+type Template struct {
+	Name          string   `json:"name" yaml:"-"`
+	Description   string   `json:"description" yaml:"description"`
+	Width         int      `json:"width" yaml:"width"`
+	Height        int      `json:"height" yaml:"height"`
+	Background    string   `json:"background,omitempty" yaml:"background"`
+	Format        string   `json:"format,omitempty" yaml:"format"`
+	Quality       int      `json:"quality,omitempty" yaml:"quality"`
+	Font          string   `json:"font,omitempty" yaml:"font"`
+	FallbackFonts []string `json:"fallback_fonts,omitempty" yaml:"fallback_fonts"`
+	Layers        []*Layer `json:"layers" yaml:"layers"`
+	ModifiedOn    int      `json:"modified_on" yaml:"-"`
+}
+
+// Layer is a box of the poster, the fields used depend on its type:
+// image draws Src, a path under the runtime root, cover and qr draw the ones given to Render,
+// all three filling the box and cropping what overflows; text draws Text, in which {name} is replaced
+// by the variable of that name, wrapped in Width when it has one; rect fills the box with Color.
+// Radius rounds the corners of all but text.
+// A layer with When is only drawn if the variable it names is set, or unset when prefixed with !
+type Layer struct {
+	Type       string  `json:"type" yaml:"type"`
+	X          int     `json:"x" yaml:"x"`
+	Y          int     `json:"y" yaml:"y"`
+	Width      int     `json:"width" yaml:"width"`
+	Height     int     `json:"height" yaml:"height"`
+	Radius     int     `json:"radius,omitempty" yaml:"radius"`
+	Color      string  `json:"color,omitempty" yaml:"color"`
+	Src        string  `json:"src,omitempty" yaml:"src"`
+	Text       string  `json:"text,omitempty" yaml:"text"`
+	Font       string  `json:"font,omitempty" yaml:"font"`
+	Size       float64 `json:"size,omitempty" yaml:"size"`
+	Align      string  `json:"align,omitempty" yaml:"align"`
+	MaxLines   int     `json:"max_lines,omitempty" yaml:"max_lines"`
+	LineHeight float64 `json:"line_height,omitempty" yaml:"line_height"`
+	When       string  `json:"when,omitempty" yaml:"when"`
+}
+
+// GetTemplatePath get the path of the templates
+func GetTemplatePath() string {
+	return setting.AppSetting.PosterTemplatePath
+}
+
+// GetTemplateFullPath get the full path of the templates
+func GetTemplateFullPath() string {
+	return setting.AppSetting.RuntimeRootPath + GetTemplatePath()
+}
+
+// IsTemplateName checks if a name can be the one of a template
+func IsTemplateName(name string) bool {
+	return nameRegexp.MatchString(name)
+}
+
+// GetTemplate gets a template by its name, nil if there is none
+func GetTemplate(name string) (*Template, error) {
+	if !IsTemplateName(name) {
+		return nil, nil
+	}
+
+	for _, ext := range exts {
+		t, err := loadTemplate(GetTemplateFullPath() + name + ext)
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		return t, err
+	}
+
+	return nil, nil
+}
+
+// GetTemplates gets the templates by name, the invalid ones being skipped
+func GetTemplates() ([]*Template, error) {
+	infos, err := ioutil.ReadDir(GetTemplateFullPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Template{}, nil
+		}
+		return nil, err
+	}
+
+	templates := make([]*Template, 0, len(infos))
+	seen := make(map[string]bool)
+	for _, info := range infos {
+		name := strings.TrimSuffix(info.Name(), filepath.Ext(info.Name()))
+		if info.IsDir() || seen[name] || !IsTemplateName(name) || !isTemplateExt(filepath.Ext(info.Name())) {
+			continue
+		}
+		seen[name] = true
+
+		t, err := GetTemplate(name)
+		if err != nil {
+			logging.Warn(err)
+			continue
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	return templates, nil
+}
+
+func isTemplateExt(ext string) bool {
+	for _, v := range exts {
+		if v == ext {
+			return true
+		}
+	}
+
+	return false
+}
+
+func loadTemplate(src string) (*Template, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var t Template
+	if filepath.Ext(src) == ".json" {
+		err = json.Unmarshal(data, &t)
+	} else {
+		err = yaml.Unmarshal(data, &t)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("poster template %s: %v", src, err)
+	}
+
+	t.Name = strings.TrimSuffix(filepath.Base(src), filepath.Ext(src))
+	t.ModifiedOn = int(info.ModTime().Unix())
+	if err := t.check(); err != nil {
+		return nil, fmt.Errorf("poster template %s: %v", src, err)
+	}
+
+	return &t, nil
+}
+
+// check checks the template can be drawn, so that mistakes show when it is loaded rather than half drawn
+func (t *Template) check() error {
+	if t.Width <= 0 || t.Height <= 0 {
+		return fmt.Errorf("width and height must be positive")
+	}
+	if t.Background != "" {
+		if _, err := parseColor(t.Background); err != nil {
+			return err
+		}
+	}
+	if t.Format != "" && t.Format != FORMAT_JPEG && t.Format != FORMAT_PNG {
+		return fmt.Errorf("unknown format %q", t.Format)
+	}
+	if t.Quality < 0 || t.Quality > 100 {
+		return fmt.Errorf("quality must be between 0 and 100")
+	}
+
+	for i, l := range t.Layers {
+		if err := l.check(t); err != nil {
+			return fmt.Errorf("layer %d: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (l *Layer) check(t *Template) error {
+	if l.Width < 0 || l.Height < 0 || l.Radius < 0 || l.MaxLines < 0 || l.LineHeight < 0 {
+		return fmt.Errorf("sizes must not be negative")
+	}
+	if l.Color != "" {
+		if _, err := parseColor(l.Color); err != nil {
+			return err
+		}
+	}
+
+	switch l.Type {
+	case LAYER_IMAGE:
+		if l.Src == "" || strings.Contains(l.Src, "..") {
+			return fmt.Errorf("src must be a path under the runtime root")
+		}
+	case LAYER_COVER, LAYER_QR, LAYER_RECT:
+		if l.Width == 0 || l.Height == 0 {
+			return fmt.Errorf("%s needs a width and a height", l.Type)
+		}
+	case LAYER_TEXT:
+		if l.Size <= 0 {
+			return fmt.Errorf("text needs a size")
+		}
+		if l.Font == "" && t.Font == "" {
+			return fmt.Errorf("text needs a font, of its own or of the template")
+		}
+		switch l.Align {
+		case "", ALIGN_LEFT:
+		case ALIGN_CENTER, ALIGN_RIGHT:
+			if l.Width == 0 {
+				return fmt.Errorf("text aligned %s needs a width", l.Align)
+			}
+		default:
+			return fmt.Errorf("unknown align %q", l.Align)
+		}
+	default:
+		return fmt.Errorf("unknown type %q", l.Type)
+	}
+
+	return nil
+}
+
+// GetExt gets the file ext of the posters drawn with the template
+func (t *Template) GetExt() string {
+	if t.Format == FORMAT_PNG {
+		return ".png"
+	}
+
+	return ".jpg"
+}
+
+// GetQrSize gets the size of the first qr layer, zero when the template has none
+func (t *Template) GetQrSize() (int, int) {
+	for _, l := range t.Layers {
+		if l.Type == LAYER_QR {
+			return l.Width, l.Height
+		}
+	}
+
+	return 0, 0
+}
@@ -0,0 +1,207 @@
+package poster
+
+import (
+	"image"
+	"strings"
+	"unicode"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// punctuation that cannot start a line stays with the character before it
+	closingPunct = "，。、；：？！）》」』】〉”’…—·,.;:?!)]}%"
+	// punctuation that cannot end a line stays with the character after it
+	openingPunct = "（《「『【〈“‘([{"
+)
+
+// fallbackFace draws every rune with the first of its fonts having a glyph for it,
+// the first font drawing the runes none of them has
+type fallbackFace struct {
+	fonts []*truetype.Font
+	faces []font.Face
+}
+
+func newFallbackFace(fonts []*truetype.Font, size float64) *fallbackFace {
+	f := &fallbackFace{fonts: fonts}
+	for _, v := range fonts {
+		f.faces = append(f.faces, truetype.NewFace(v, &truetype.Options{Size: size, DPI: 72}))
+	}
+
+	return f
+}
+
+func (f *fallbackFace) pick(r rune) font.Face {
+	for i, v := range f.fonts {
+		if v.Index(r) != 0 {
+			return f.faces[i]
+		}
+	}
+
+	return f.faces[0]
+}
+
+func (f *fallbackFace) has(r rune) bool {
+	for _, v := range f.fonts {
+		if v.Index(r) != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *fallbackFace) Close() error {
+	for _, v := range f.faces {
+		v.Close()
+	}
+
+	return nil
+}
+
+func (f *fallbackFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return f.pick(r).Glyph(dot, r)
+}
+
+func (f *fallbackFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return f.pick(r).GlyphBounds(r)
+}
+
+func (f *fallbackFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return f.pick(r).GlyphAdvance(r)
+}
+
+// Kern only kerns runes drawn with the same font
+func (f *fallbackFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	if face := f.pick(r0); face == f.pick(r1) {
+		return face.Kern(r0, r1)
+	}
+
+	return 0
+}
+
+func (f *fallbackFace) Metrics() font.Metrics {
+	return f.faces[0].Metrics()
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303f) || (r >= 0xff00 && r <= 0xffef)
+}
+
+// tokenize splits a paragraph where its lines may break: around spaces, and between CJK characters
+func tokenize(paragraph string) []string {
+	const (
+		none = iota
+		word
+		space
+		opening
+	)
+
+	var (
+		tokens []string
+		cur    []rune
+		kind   = none
+	)
+	flush := func() {
+		if len(cur) > 0 {
+			tokens = append(tokens, string(cur))
+		}
+		cur, kind = nil, none
+	}
+
+	for _, r := range paragraph {
+		switch {
+		case unicode.IsSpace(r):
+			if kind != space {
+				flush()
+			}
+			cur, kind = append(cur, r), space
+		case strings.ContainsRune(closingPunct, r) && kind != space && len(cur)+len(tokens) > 0:
+			if len(cur) > 0 {
+				cur = append(cur, r)
+			} else {
+				tokens[len(tokens)-1] += string(r)
+			}
+		case strings.ContainsRune(openingPunct, r):
+			if kind != opening {
+				flush()
+			}
+			cur, kind = append(cur, r), opening
+		case isCJK(r):
+			if kind != opening {
+				flush()
+			}
+			cur = append(cur, r)
+			flush()
+		default:
+			if kind != word && kind != opening {
+				flush()
+			}
+			cur, kind = append(cur, r), word
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// wrap breaks the text into the lines fitting in the width, between words or CJK characters,
+// and between the characters of a word wider than a line; a width of 0 only breaks at newlines
+func wrap(face font.Face, text string, width int) []string {
+	var lines []string
+	max := fixed.I(width)
+	for _, paragraph := range strings.Split(text, "\n") {
+		if width <= 0 {
+			lines = append(lines, paragraph)
+			continue
+		}
+
+		line := ""
+		for _, token := range tokenize(paragraph) {
+			blank := strings.TrimSpace(token) == ""
+			if blank && line == "" {
+				continue
+			}
+			if font.MeasureString(face, line+token) <= max {
+				line += token
+				continue
+			}
+
+			if line != "" {
+				lines = append(lines, strings.TrimRightFunc(line, unicode.IsSpace))
+				line = ""
+			}
+			if blank {
+				continue
+			}
+			for _, r := range token {
+				if line != "" && font.MeasureString(face, line+string(r)) > max {
+					lines = append(lines, line)
+					line = ""
+				}
+				line += string(r)
+			}
+		}
+		lines = append(lines, strings.TrimRightFunc(line, unicode.IsSpace))
+	}
+
+	return lines
+}
+
+// ellipsize ends the line with an ellipsis, dropping the characters it would not fit with
+func ellipsize(face *fallbackFace, line string, width int) string {
+	ellipsis := "…"
+	if !face.has('…') {
+		ellipsis = "..."
+	}
+
+	runes := []rune(strings.TrimRightFunc(line, unicode.IsSpace))
+	for width > 0 && len(runes) > 0 && font.MeasureString(face, string(runes)+ellipsis) > fixed.I(width) {
+		runes = runes[:len(runes)-1]
+	}
+
+	return strings.TrimRightFunc(string(runes), unicode.IsSpace) + ellipsis
+}
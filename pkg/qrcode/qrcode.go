@@ -1,7 +1,13 @@
 package qrcode
 
 import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
 	"image/jpeg"
+	"image/png"
+	"io"
 
 	"github.com/boombuler/barcode"
 	"github.com/boombuler/barcode/qr"
@@ -12,19 +18,21 @@ import (
 )
 
 type QrCode struct {
-	URL    string
-	Width  int
-	Height int
-	Ext    string
-	Level  qr.ErrorCorrectionLevel
-	Mode   qr.Encoding
+	URL     string
+	Width   int
+	Height  int
+	Ext     string
+	Level   qr.ErrorCorrectionLevel
+	Mode    qr.Encoding
+	Quality int
 }
 
 const (
 	EXT_JPG = ".jpg"
+	EXT_PNG = ".png"
 )
 
-// NewQrCode initialize instance
+// NewQrCode initialize instance, encoded as a JPEG of the default quality unless its Ext and Quality are set
 func NewQrCode(url string, width, height int, level qr.ErrorCorrectionLevel, mode qr.Encoding) *QrCode {
 	return &QrCode{
 		URL:    url,
@@ -61,9 +69,9 @@ func (q *QrCode) GetQrCodeExt() string {
 	return q.Ext
 }
 
-// Encode generate QR code
+// Encode generate QR code as a PNG or JPEG file after its ext, named after its url, size and quality
 func (q *QrCode) Encode(path string) (string, string, error) {
-	name := GetQrCodeFileName(q.URL) + q.GetQrCodeExt()
+	name := GetQrCodeFileName(fmt.Sprintf("%s_%dx%d_%d", q.URL, q.Width, q.Height, q.Quality)) + q.GetQrCodeExt()
 	src := path + name
 	if file.CheckNotExist(src) == true {
 		code, err := qr.Encode(q.URL, q.Level, q.Mode)
@@ -76,13 +84,9 @@ func (q *QrCode) Encode(path string) (string, string, error) {
 			return "", "", err
 		}
 
-		f, err := file.MustOpen(name, path)
-		if err != nil {
-			return "", "", err
-		}
-		defer f.Close()
-
-		err = jpeg.Encode(f, code, nil)
+		err = file.WriteFile(path, name, func(w io.Writer) error {
+			return EncodeImage(w, code, q.Ext, q.Quality)
+		})
 		if err != nil {
 			return "", "", err
 		}
@@ -90,3 +94,22 @@ func (q *QrCode) Encode(path string) (string, string, error) {
 
 	return name, path, nil
 }
+
+// EncodeImage encodes the image as a PNG or a JPEG after the ext, a JPEG being drawn on white
+// as it has no transparency; a quality of 0 is the default one
+func EncodeImage(w io.Writer, img image.Image, ext string, quality int) error {
+	if ext == EXT_PNG {
+		return png.Encode(w, img)
+	}
+
+	flattened := image.NewRGBA(img.Bounds())
+	draw.Draw(flattened, flattened.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flattened, flattened.Bounds(), img, img.Bounds().Min, draw.Over)
+
+	var options *jpeg.Options
+	if quality > 0 {
+		options = &jpeg.Options{Quality: quality}
+	}
+
+	return jpeg.Encode(w, flattened, options)
+}
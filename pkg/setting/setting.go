@@ -12,15 +12,40 @@ type App struct {
 	PageSize  int
 	PrefixUrl string
 
+	ArticlePath string
+	TagPath     string
+	PreviewPath string
+
+	Editors             []string
+	ArticleUnlockExpire time.Duration
+	PreviewExpire       time.Duration
+
+	Langs []string
+
+	ArchiveTimeZone string
+	ArchiveLocation *time.Location `ini:"-"`
+
 	RuntimeRootPath string
 
 	ImageSavePath  string
 	ImageMaxSize   int
 	ImageAllowExts []string
 
+	ImageGracePeriod   time.Duration
+	ImageCleanInterval time.Duration
+
+	ExportExpire        time.Duration
+	ExportCleanInterval time.Duration
+
 	ExportSavePath string
+	TagSheetName   string
 	QrCodeSavePath string
 	FontSavePath   string
+	SitemapPath    string
+
+	PosterTemplatePath  string
+	PosterTemplate      string
+	PosterFallbackFonts []string
 
 	LogSavePath string
 	LogSaveName string
@@ -60,6 +85,24 @@ type Redis struct {
 
 var RedisSetting = &Redis{}
 
+type Feed struct {
+	Title       string
+	Description string
+	Author      string
+	Size        int
+}
+
+var FeedSetting = &Feed{}
+
+type Sitemap struct {
+	MaxUrls         int
+	RobotsUserAgent string
+	RobotsAllow     []string
+	RobotsDisallow  []string
+}
+
+var SitemapSetting = &Sitemap{}
+
 var cfg *ini.File
 
 // Setup initialize the configuration instance
@@ -74,11 +117,24 @@ func Setup() {
 	mapTo("server", ServerSetting)
 	mapTo("database", DatabaseSetting)
 	mapTo("redis", RedisSetting)
+	mapTo("feed", FeedSetting)
+	mapTo("sitemap", SitemapSetting)
 
 	AppSetting.ImageMaxSize = AppSetting.ImageMaxSize * 1024 * 1024
+	AppSetting.ArticleUnlockExpire = AppSetting.ArticleUnlockExpire * time.Minute
+	AppSetting.PreviewExpire = AppSetting.PreviewExpire * time.Hour
+	AppSetting.ImageGracePeriod = AppSetting.ImageGracePeriod * time.Hour
+	AppSetting.ImageCleanInterval = AppSetting.ImageCleanInterval * time.Hour
+	AppSetting.ExportExpire = AppSetting.ExportExpire * time.Hour
+	AppSetting.ExportCleanInterval = AppSetting.ExportCleanInterval * time.Hour
 	ServerSetting.ReadTimeout = ServerSetting.ReadTimeout * time.Second
 	ServerSetting.WriteTimeout = ServerSetting.WriteTimeout * time.Second
 	RedisSetting.IdleTimeout = RedisSetting.IdleTimeout * time.Second
+
+	AppSetting.ArchiveLocation, err = time.LoadLocation(AppSetting.ArchiveTimeZone)
+	if err != nil {
+		log.Fatalf("setting.Setup, fail to load time zone '%s': %v", AppSetting.ArchiveTimeZone, err)
+	}
 }
 
 // mapTo map section
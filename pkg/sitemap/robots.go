@@ -0,0 +1,44 @@
+package sitemap
+
+import (
+	"fmt"
+	"io"
+)
+
+type Robots struct {
+	UserAgent string
+	Allow     []string
+	Disallow  []string
+	Sitemap   string
+}
+
+// WriteRobots write the robots.txt rules
+func WriteRobots(w io.Writer, r *Robots) error {
+	userAgent := r.UserAgent
+	if userAgent == "" {
+		userAgent = "*"
+	}
+
+	lines := []string{"User-agent: " + userAgent}
+	for _, v := range r.Allow {
+		if v != "" {
+			lines = append(lines, "Allow: "+v)
+		}
+	}
+	for _, v := range r.Disallow {
+		if v != "" {
+			lines = append(lines, "Disallow: "+v)
+		}
+	}
+	if r.Sitemap != "" {
+		lines = append(lines, "", "Sitemap: "+r.Sitemap)
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+const (
+	XMLNS    = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	MAX_URLS = 50000
+)
+
+type Url struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name  `xml:"urlset"`
+	Xmlns   string    `xml:"xmlns,attr"`
+	Urls    []*urlTag `xml:"url"`
+}
+
+type urlTag struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name  `xml:"sitemapindex"`
+	Xmlns    string    `xml:"xmlns,attr"`
+	Sitemaps []*urlTag `xml:"sitemap"`
+}
+
+// WriteUrlSet write a sitemap containing the urls
+func WriteUrlSet(w io.Writer, urls []*Url) error {
+	set := &urlSet{Xmlns: XMLNS}
+	for _, url := range urls {
+		set.Urls = append(set.Urls, newUrlTag(url))
+	}
+
+	return write(w, set)
+}
+
+// WriteIndex write a sitemap index pointing to the child sitemaps
+func WriteIndex(w io.Writer, sitemaps []*Url) error {
+	index := &sitemapIndex{Xmlns: XMLNS}
+	for _, sitemap := range sitemaps {
+		index.Sitemaps = append(index.Sitemaps, newUrlTag(sitemap))
+	}
+
+	return write(w, index)
+}
+
+func newUrlTag(url *Url) *urlTag {
+	tag := &urlTag{Loc: url.Loc}
+	if !url.LastMod.IsZero() {
+		tag.LastMod = url.LastMod.UTC().Format(time.RFC3339)
+	}
+
+	return tag
+}
+
+func write(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	return enc.Encode(v)
+}
+
+// GetSitemapFullUrl get the full access path of the sitemap file
+func GetSitemapFullUrl(name string) string {
+	return setting.AppSetting.PrefixUrl + "/" + GetSitemapPath() + name
+}
+
+// GetSitemapPath get the relative save path of the sitemap file
+func GetSitemapPath() string {
+	return setting.AppSetting.SitemapPath
+}
+
+// GetSitemapFullPath get the full save path of the sitemap file
+func GetSitemapFullPath() string {
+	return setting.AppSetting.RuntimeRootPath + GetSitemapPath()
+}
@@ -0,0 +1,74 @@
+package util
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+// GetDefaultLang get the language articles are written in unless told otherwise
+func GetDefaultLang() string {
+	if len(setting.AppSetting.Langs) == 0 {
+		return ""
+	}
+
+	return setting.AppSetting.Langs[0]
+}
+
+// IsLang checks if articles are published in the language
+func IsLang(lang string) bool {
+	for _, l := range setting.AppSetting.Langs {
+		if l == lang {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NegotiateLang get the published language best matching an Accept-Language header, "" if none does
+func NegotiateLang(header string) string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var ranges []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, weighted{tag, q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	for _, r := range ranges {
+		if r.tag == "*" {
+			return GetDefaultLang()
+		}
+		for _, lang := range setting.AppSetting.Langs {
+			// zh-CN is served by zh
+			if r.tag == lang || strings.SplitN(r.tag, "-", 2)[0] == lang {
+				return lang
+			}
+		}
+	}
+
+	return ""
+}
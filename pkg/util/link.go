@@ -0,0 +1,23 @@
+package util
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+// GetArticleFullUrl get the full access path of the article
+func GetArticleFullUrl(id int) string {
+	return setting.AppSetting.PrefixUrl + "/" + setting.AppSetting.ArticlePath + strconv.Itoa(id)
+}
+
+// GetTagFullUrl get the full access path of the tag
+func GetTagFullUrl(id int) string {
+	return setting.AppSetting.PrefixUrl + "/" + setting.AppSetting.TagPath + strconv.Itoa(id)
+}
+
+// GetPreviewFullUrl get the full access path of the signed preview of the article
+func GetPreviewFullUrl(id int, token string) string {
+	return setting.AppSetting.PrefixUrl + "/" + setting.AppSetting.PreviewPath + strconv.Itoa(id) + "?token=" + url.QueryEscape(token)
+}
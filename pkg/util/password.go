@@ -0,0 +1,57 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	PASSWORD_ALGORITHM  = "pbkdf2_sha256"
+	PASSWORD_ITERATIONS = 100000
+
+	passwordSaltSize = 16
+	passwordKeySize  = 32
+)
+
+// HashPassword derives a salted PBKDF2-SHA256 hash of the password, encoded with its parameters
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := pbkdf2.Key([]byte(password), salt, PASSWORD_ITERATIONS, passwordKeySize, sha256.New)
+
+	return fmt.Sprintf("%s$%d$%s$%s", PASSWORD_ALGORITHM, PASSWORD_ITERATIONS,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// CheckPassword checks the password against a hash made by HashPassword
+func CheckPassword(password, encoded string) bool {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 || parts[0] != PASSWORD_ALGORITHM {
+		return false
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations < 1 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(key, pbkdf2.Key([]byte(password), salt, iterations, len(key), sha256.New)) == 1
+}
@@ -0,0 +1,47 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignToken signs the payload with the application secret, the token is valid until expires
+func SignToken(payload string, expires time.Time) string {
+	data := payload + "." + strconv.FormatInt(expires.Unix(), 10)
+
+	return data + "." + sign(data)
+}
+
+// ParseSignedToken verifies a token made by SignToken and returns its payload
+func ParseSignedToken(token string) (string, bool) {
+	i := strings.LastIndex(token, ".")
+	if i < 0 {
+		return "", false
+	}
+	data, mac := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(mac), []byte(sign(data))) {
+		return "", false
+	}
+
+	j := strings.LastIndex(data, ".")
+	if j < 0 {
+		return "", false
+	}
+	expires, err := strconv.ParseInt(data[j+1:], 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return "", false
+	}
+
+	return data[:j], true
+}
+
+func sign(data string) string {
+	h := hmac.New(sha256.New, jwtSecret)
+	h.Write([]byte(data))
+
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/feed"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/service/feed_service"
+	"github.com/EDDYCJY/go-gin-example/service/tag_service"
+)
+
+// @Summary Get the RSS 2.0 feed of published articles
+// @Produce  xml
+// @Param id path int false "TagID"
+// @Success 200 {string} string
+// @Failure 500 {object} app.Response
+// @Router /feed/rss.xml [get]
+func GetRSSFeed(c *gin.Context) {
+	getFeed(c, feed.RSS)
+}
+
+// @Summary Get the Atom feed of published articles
+// @Produce  xml
+// @Param id path int false "TagID"
+// @Success 200 {string} string
+// @Failure 500 {object} app.Response
+// @Router /feed/atom.xml [get]
+func GetAtomFeed(c *gin.Context) {
+	getFeed(c, feed.ATOM)
+}
+
+func getFeed(c *gin.Context, format string) {
+	appG := app.Gin{C: c}
+	tagId := -1
+	if arg := c.Param("id"); arg != "" {
+		tagId = com.StrTo(arg).MustInt()
+		valid := validation.Validation{}
+		valid.Min(tagId, 1, "id")
+		if valid.HasErrors() {
+			app.MarkErrors(valid.Errors)
+			appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+			return
+		}
+
+		tagService := tag_service.Tag{ID: tagId}
+		exists, err := tagService.ExistByID()
+		if err != nil {
+			appG.Response(http.StatusInternalServerError, e.ERROR_EXIST_TAG_FAIL, nil)
+			return
+		}
+		if !exists {
+			appG.Response(http.StatusNotFound, e.ERROR_NOT_EXIST_TAG, nil)
+			return
+		}
+	}
+
+	feedService := feed_service.Feed{TagID: tagId, Format: format}
+	etag, lastModified, err := feedService.GetETag()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_FEED_FAIL, nil)
+		return
+	}
+	if appG.CheckNotModified(etag, lastModified) {
+		return
+	}
+
+	data, err := feedService.Generate()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_FEED_FAIL, nil)
+		return
+	}
+
+	c.Data(http.StatusOK, feed.GetContentType(format), data)
+}
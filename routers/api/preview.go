@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+)
+
+// @Summary Get the preview of an article through a signed link, whatever its state
+// @Produce  json
+// @Param id path int true "ID"
+// @Param token query string true "Token"
+// @Success 200 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /preview/articles/{id} [get]
+func GetPreview(c *gin.Context) {
+	appG := app.Gin{C: c}
+	id := com.StrTo(c.Param("id")).MustInt()
+	token := c.Query("token")
+	valid := validation.Validation{}
+	valid.Min(id, 1, "id")
+	valid.Required(token, "token")
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	articleService := article_service.Article{ID: id}
+	exists, err := articleService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE, nil)
+		return
+	}
+
+	article, err := articleService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_ARTICLE_FAIL, nil)
+		return
+	}
+
+	if !article_service.CheckPreview(article, token) {
+		appG.Response(http.StatusForbidden, e.ERROR_PREVIEW_INVALID, nil)
+		return
+	}
+
+	// previews are shared privately and must stay out of search engines and shared caches
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+	c.Header("Cache-Control", "private, no-store")
+	appG.Response(http.StatusOK, e.SUCCESS, article)
+}
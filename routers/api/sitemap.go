@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/service/sitemap_service"
+)
+
+// @Summary Get the sitemap of published articles and tags
+// @Produce  xml
+// @Success 200 {string} string
+// @Failure 500 {object} app.Response
+// @Router /sitemap.xml [get]
+func GetSitemap(c *gin.Context) {
+	appG := app.Gin{C: c}
+	src, err := sitemap_service.GetSitemap()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GEN_SITEMAP_FAIL, nil)
+		return
+	}
+
+	c.File(src)
+}
+
+// @Summary Get robots.txt
+// @Produce  plain
+// @Success 200 {string} string
+// @Failure 500 {object} app.Response
+// @Router /robots.txt [get]
+func GetRobots(c *gin.Context) {
+	appG := app.Gin{C: c}
+	src, err := sitemap_service.GetRobots()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GEN_SITEMAP_FAIL, nil)
+		return
+	}
+
+	c.File(src)
+}
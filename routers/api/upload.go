@@ -2,18 +2,24 @@ package api
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/astaxie/beego/validation"
 	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
 
 	"github.com/EDDYCJY/go-gin-example/pkg/app"
 	"github.com/EDDYCJY/go-gin-example/pkg/e"
 	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
 	"github.com/EDDYCJY/go-gin-example/pkg/upload"
+	"github.com/EDDYCJY/go-gin-example/service/upload_service"
 )
 
 // @Summary Import Image
 // @Produce  json
 // @Param image formData file true "Image File"
+// @Param created_by formData string false "CreatedBy"
 // @Success 200 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Router /api/v1/tags/import [post]
@@ -31,6 +37,15 @@ func UploadImage(c *gin.Context) {
 		return
 	}
 
+	createdBy := c.PostForm("created_by")
+	valid := validation.Validation{}
+	valid.MaxSize(createdBy, 100, "created_by")
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
 	imageName := upload.GetImageName(image.Filename)
 	fullPath := upload.GetImageFullPath()
 	savePath := upload.GetImagePath()
@@ -54,8 +69,47 @@ func UploadImage(c *gin.Context) {
 		return
 	}
 
+	if err := upload_service.Record(imageName, image.Size, createdBy); err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_UPLOAD_SAVE_IMAGE_FAIL, nil)
+		return
+	}
+
 	appG.Response(http.StatusOK, e.SUCCESS, map[string]string{
 		"image_url":      upload.GetImageFullUrl(imageName),
 		"image_save_url": savePath + imageName,
 	})
 }
+
+// @Summary Delete the uploaded images no longer referenced
+// @Produce  json
+// @Param grace body int false "Grace period in hours, images uploaded more recently are kept"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/uploads/clean [post]
+func CleanUploads(c *gin.Context) {
+	appG := app.Gin{C: c}
+	valid := validation.Validation{}
+
+	grace := setting.AppSetting.ImageGracePeriod
+	if arg := c.PostForm("grace"); arg != "" {
+		hours := com.StrTo(arg).MustInt()
+		valid.Min(hours, 0, "grace")
+		grace = time.Duration(hours) * time.Hour
+	}
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	result, err := upload_service.Clean(grace)
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_UPLOAD_CLEAN_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, result)
+}
@@ -0,0 +1,76 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+)
+
+// @Summary Get the number of articles created in each month, latest first
+// @Produce  json
+// @Param tag_id query int false "TagID"
+// @Param state query int false "State"
+// @Param lang query string false "Lang"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/articles/archive [get]
+func GetArticleArchive(c *gin.Context) {
+	appG := app.Gin{C: c}
+	valid := validation.Validation{}
+
+	state := -1
+	if arg := c.Query("state"); arg != "" {
+		state = com.StrTo(arg).MustInt()
+		valid.Range(state, 0, 1, "state")
+	}
+
+	tagId := -1
+	if arg := c.Query("tag_id"); arg != "" {
+		tagId = com.StrTo(arg).MustInt()
+		valid.Min(tagId, 1, "tag_id")
+	}
+
+	lang := c.Query("lang")
+	if lang != "" {
+		validLang(&valid, lang)
+	}
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	articleService := article_service.Article{
+		TagID:  tagId,
+		State:  state,
+		Lang:   lang,
+		Viewer: article_service.NewViewer(app.GetClaims(c)),
+	}
+	buckets, err := articleService.GetArchive()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_ARTICLE_ARCHIVE_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": buckets,
+	})
+}
+
+// validMonth checks an archive month filter, the month requires a year
+func validMonth(v *validation.Validation, year, month int) {
+	if year != 0 {
+		v.Range(year, 1970, 9999, "year")
+	}
+	if month != 0 {
+		v.Required(year, "year")
+		v.Range(month, 1, 12, "month")
+	}
+}
@@ -2,25 +2,34 @@ package v1
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/unknwon/com"
 	"github.com/astaxie/beego/validation"
-	"github.com/boombuler/barcode/qr"
 	"github.com/gin-gonic/gin"
 
+	"github.com/EDDYCJY/go-gin-example/models"
 	"github.com/EDDYCJY/go-gin-example/pkg/app"
 	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/export"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/poster"
 	"github.com/EDDYCJY/go-gin-example/pkg/qrcode"
 	"github.com/EDDYCJY/go-gin-example/pkg/setting"
 	"github.com/EDDYCJY/go-gin-example/pkg/util"
 	"github.com/EDDYCJY/go-gin-example/service/article_service"
+	"github.com/EDDYCJY/go-gin-example/service/series_service"
 	"github.com/EDDYCJY/go-gin-example/service/tag_service"
 )
 
 // @Summary Get a single article
 // @Produce  json
 // @Param id path int true "ID"
+// @Param lang query string false "Lang, negotiated from Accept-Language if empty"
+// @Param If-None-Match header string false "ETag"
 // @Success 200 {object} app.Response
+// @Failure 403 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Router /api/v1/articles/{id} [get]
 func GetArticle(c *gin.Context) {
@@ -29,13 +38,20 @@ func GetArticle(c *gin.Context) {
 	valid := validation.Validation{}
 	valid.Min(id, 1, "id")
 
+	lang := c.Query("lang")
+	if lang != "" {
+		validLang(&valid, lang)
+	} else {
+		lang = util.NegotiateLang(c.GetHeader("Accept-Language"))
+	}
+
 	if valid.HasErrors() {
 		app.MarkErrors(valid.Errors)
 		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
 		return
 	}
 
-	articleService := article_service.Article{ID: id}
+	articleService := article_service.Article{ID: id, Lang: lang}
 	exists, err := articleService.ExistByID()
 	if err != nil {
 		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
@@ -52,7 +68,81 @@ func GetArticle(c *gin.Context) {
 		return
 	}
 
-	appG.Response(http.StatusOK, e.SUCCESS, article)
+	if !checkArticleAccess(appG, article) {
+		return
+	}
+
+	nav, err := series_service.GetNavigation(article.ID)
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_SERIES_FAIL, nil)
+		return
+	}
+
+	c.Header("Content-Language", article.Lang)
+	c.Header("Vary", "Accept-Language")
+
+	if nav == nil && lang == "" {
+		if appG.CheckNotModified(article_service.GetETag(article), time.Unix(int64(article.ModifiedOn), 0)) {
+			return
+		}
+
+		appG.Response(http.StatusOK, e.SUCCESS, article)
+		return
+	}
+
+	var view interface{} = article
+	if nav != nil {
+		view = ArticleWithSeries{Article: article, Series: nav}
+	}
+
+	// translations and the navigation change apart from the article, so only the entity tag can tell freshness
+	if appG.CheckNotModified(article_service.GetViewETag(article, view), time.Time{}) {
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, view)
+}
+
+type ArticleWithSeries struct {
+	*models.Article
+
+	Series *series_service.Navigation `json:"series"`
+}
+
+// checkArticleAccess responds as if a private article did not exist to those who cannot manage it,
+// and asks for the password of a protected one not unlocked yet
+func checkArticleAccess(appG app.Gin, article *models.Article) bool {
+	viewer := article_service.NewViewer(app.GetClaims(appG.C))
+	switch article.Visibility {
+	case models.VISIBILITY_PRIVATE:
+		if !viewer.CanManage(article) {
+			appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE, nil)
+			return false
+		}
+	case models.VISIBILITY_PASSWORD:
+		token, _ := appG.C.Cookie(article_service.GetUnlockCookieName(article.ID))
+		if !viewer.CanManage(article) && !article_service.CheckUnlockToken(article, token) {
+			appG.Response(http.StatusForbidden, e.ERROR_ARTICLE_PASSWORD_REQUIRED, nil)
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkArticleEdit responds as if the article did not exist to those who may not change it
+func checkArticleEdit(appG app.Gin, articleService *article_service.Article) bool {
+	article, err := articleService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_ARTICLE_FAIL, nil)
+		return false
+	}
+	if !article_service.NewViewer(app.GetClaims(appG.C)).CanEdit(article) {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE, nil)
+		return false
+	}
+
+	return true
 }
 
 // @Summary Get multiple articles
@@ -60,6 +150,10 @@ func GetArticle(c *gin.Context) {
 // @Param tag_id body int false "TagID"
 // @Param state body int false "State"
 // @Param created_by body int false "CreatedBy"
+// @Param visibility body string false "Visibility"
+// @Param lang body string false "Lang"
+// @Param year body int false "Year created in"
+// @Param month body int false "Month created in, requires the year"
 // @Success 200 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Router /api/v1/articles [get]
@@ -79,6 +173,20 @@ func GetArticles(c *gin.Context) {
 		valid.Min(tagId, 1, "tag_id")
 	}
 
+	visibility := c.PostForm("visibility")
+	if visibility != "" {
+		validVisibility(&valid, visibility)
+	}
+
+	lang := c.PostForm("lang")
+	if lang != "" {
+		validLang(&valid, lang)
+	}
+
+	year := com.StrTo(c.PostForm("year")).MustInt()
+	month := com.StrTo(c.PostForm("month")).MustInt()
+	validMonth(&valid, year, month)
+
 	if valid.HasErrors() {
 		app.MarkErrors(valid.Errors)
 		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
@@ -86,10 +194,15 @@ func GetArticles(c *gin.Context) {
 	}
 
 	articleService := article_service.Article{
-		TagID:    tagId,
-		State:    state,
-		PageNum:  util.GetPage(c),
-		PageSize: setting.AppSetting.PageSize,
+		TagID:      tagId,
+		State:      state,
+		Visibility: visibility,
+		Lang:       lang,
+		Year:       year,
+		Month:      month,
+		Viewer:     article_service.NewViewer(app.GetClaims(c)),
+		PageNum:    util.GetPage(c),
+		PageSize:   setting.AppSetting.PageSize,
 	}
 
 	total, err := articleService.Count()
@@ -114,21 +227,41 @@ func GetArticles(c *gin.Context) {
 type AddArticleForm struct {
 	TagID         int    `form:"tag_id" valid:"Required;Min(1)"`
 	Title         string `form:"title" valid:"Required;MaxSize(100)"`
+	Slug          string `form:"slug" valid:"AlphaDash;MaxSize(255)"`
 	Desc          string `form:"desc" valid:"Required;MaxSize(255)"`
 	Content       string `form:"content" valid:"Required;MaxSize(65535)"`
 	CreatedBy     string `form:"created_by" valid:"Required;MaxSize(100)"`
 	CoverImageUrl string `form:"cover_image_url" valid:"Required;MaxSize(255)"`
 	State         int    `form:"state" valid:"Range(0,1)"`
+	Visibility    string `form:"visibility"`
+	Password      string `form:"password" valid:"MaxSize(100)"`
+	Lang          string `form:"lang"`
+}
+
+func (f *AddArticleForm) Valid(v *validation.Validation) {
+	if f.Visibility != "" {
+		validVisibility(v, f.Visibility)
+	}
+	if f.Lang != "" {
+		validLang(v, f.Lang)
+	}
+	if f.Visibility == models.VISIBILITY_PASSWORD {
+		v.Required(f.Password, "password")
+	}
 }
 
 // @Summary Add article
 // @Produce  json
 // @Param tag_id body int true "TagID"
 // @Param title body string true "Title"
+// @Param slug body string false "Slug"
 // @Param desc body string true "Desc"
 // @Param content body string true "Content"
 // @Param created_by body string true "CreatedBy"
 // @Param state body int true "State"
+// @Param visibility body string false "Visibility: public, unlisted, private or password"
+// @Param password body string false "Password, required by the password visibility"
+// @Param lang body string false "Lang, the default language if empty"
 // @Success 200 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Router /api/v1/articles [post]
@@ -159,13 +292,32 @@ func AddArticle(c *gin.Context) {
 	articleService := article_service.Article{
 		TagID:         form.TagID,
 		Title:         form.Title,
+		Slug:          form.Slug,
 		Desc:          form.Desc,
 		Content:       form.Content,
 		CoverImageUrl: form.CoverImageUrl,
 		State:         form.State,
 		CreatedBy:     form.CreatedBy,
+		Visibility:    form.Visibility,
+		Password:      form.Password,
+		Lang:          form.Lang,
+	}
+	exists, err = articleService.ExistBySlug()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
+		return
+	}
+	if exists {
+		appG.Response(http.StatusOK, e.ERROR_EXIST_ARTICLE_SLUG, nil)
+		return
+	}
+
+	err = articleService.Add()
+	if err == article_service.ErrPasswordNotSet {
+		appG.Response(http.StatusBadRequest, e.ERROR_ARTICLE_PASSWORD_NOT_SET, nil)
+		return
 	}
-	if err := articleService.Add(); err != nil {
+	if err != nil {
 		appG.Response(http.StatusInternalServerError, e.ERROR_ADD_ARTICLE_FAIL, nil)
 		return
 	}
@@ -177,11 +329,24 @@ type EditArticleForm struct {
 	ID            int    `form:"id" valid:"Required;Min(1)"`
 	TagID         int    `form:"tag_id" valid:"Required;Min(1)"`
 	Title         string `form:"title" valid:"Required;MaxSize(100)"`
+	Slug          string `form:"slug" valid:"AlphaDash;MaxSize(255)"`
 	Desc          string `form:"desc" valid:"Required;MaxSize(255)"`
 	Content       string `form:"content" valid:"Required;MaxSize(65535)"`
 	ModifiedBy    string `form:"modified_by" valid:"Required;MaxSize(100)"`
 	CoverImageUrl string `form:"cover_image_url" valid:"Required;MaxSize(255)"`
 	State         int    `form:"state" valid:"Range(0,1)"`
+	Visibility    string `form:"visibility"`
+	Password      string `form:"password" valid:"MaxSize(100)"`
+	Lang          string `form:"lang"`
+}
+
+func (f *EditArticleForm) Valid(v *validation.Validation) {
+	if f.Visibility != "" {
+		validVisibility(v, f.Visibility)
+	}
+	if f.Lang != "" {
+		validLang(v, f.Lang)
+	}
 }
 
 // @Summary Update article
@@ -189,11 +354,17 @@ type EditArticleForm struct {
 // @Param id path int true "ID"
 // @Param tag_id body string false "TagID"
 // @Param title body string false "Title"
+// @Param slug body string false "Slug"
 // @Param desc body string false "Desc"
 // @Param content body string false "Content"
 // @Param modified_by body string true "ModifiedBy"
 // @Param state body int false "State"
+// @Param visibility body string false "Visibility: public, unlisted, private or password, kept if empty"
+// @Param password body string false "Password, kept if empty"
+// @Param lang body string false "Lang, kept if empty"
+// @Param If-Match header string false "ETag"
 // @Success 200 {object} app.Response
+// @Failure 412 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Router /api/v1/articles/{id} [put]
 func EditArticle(c *gin.Context) {
@@ -212,12 +383,21 @@ func EditArticle(c *gin.Context) {
 		ID:            form.ID,
 		TagID:         form.TagID,
 		Title:         form.Title,
+		Slug:          form.Slug,
 		Desc:          form.Desc,
 		Content:       form.Content,
 		CoverImageUrl: form.CoverImageUrl,
 		ModifiedBy:    form.ModifiedBy,
 		State:         form.State,
+		Visibility:    form.Visibility,
+		Password:      form.Password,
+		Lang:          form.Lang,
 	}
+	if !bindIfMatch(c, &articleService) {
+		appG.Response(http.StatusPreconditionFailed, e.ERROR_ARTICLE_PRECONDITION_FAILED, nil)
+		return
+	}
+
 	exists, err := articleService.ExistByID()
 	if err != nil {
 		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
@@ -227,6 +407,9 @@ func EditArticle(c *gin.Context) {
 		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE, nil)
 		return
 	}
+	if !checkArticleEdit(appG, &articleService) {
+		return
+	}
 
 	tagService := tag_service.Tag{ID: form.TagID}
 	exists, err = tagService.ExistByID()
@@ -240,7 +423,212 @@ func EditArticle(c *gin.Context) {
 		return
 	}
 
+	exists, err = articleService.ExistBySlug()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
+		return
+	}
+	if exists {
+		appG.Response(http.StatusOK, e.ERROR_EXIST_ARTICLE_SLUG, nil)
+		return
+	}
+
 	err = articleService.Edit()
+	if err == article_service.ErrPreconditionFailed {
+		appG.Response(http.StatusPreconditionFailed, e.ERROR_ARTICLE_PRECONDITION_FAILED, nil)
+		return
+	}
+	if err == article_service.ErrPasswordNotSet {
+		appG.Response(http.StatusBadRequest, e.ERROR_ARTICLE_PASSWORD_NOT_SET, nil)
+		return
+	}
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_EDIT_ARTICLE_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+type PatchArticleForm struct {
+	TagID         *int    `json:"tag_id"`
+	Title         *string `json:"title"`
+	Slug          *string `json:"slug"`
+	Desc          *string `json:"desc"`
+	Content       *string `json:"content"`
+	CoverImageUrl *string `json:"cover_image_url"`
+	State         *int    `json:"state"`
+	Visibility    *string `json:"visibility"`
+	Password      *string `json:"password"`
+	Lang          *string `json:"lang"`
+	ModifiedBy    *string `json:"modified_by"`
+}
+
+func (f *PatchArticleForm) Valid(v *validation.Validation) {
+	if f.TagID != nil {
+		v.Min(*f.TagID, 1, "tag_id")
+	}
+	if f.Title != nil {
+		v.Required(*f.Title, "title")
+		v.MaxSize(*f.Title, 100, "title")
+	}
+	if f.Slug != nil {
+		v.AlphaDash(*f.Slug, "slug")
+		v.MaxSize(*f.Slug, 255, "slug")
+	}
+	if f.Desc != nil {
+		v.Required(*f.Desc, "desc")
+		v.MaxSize(*f.Desc, 255, "desc")
+	}
+	if f.Content != nil {
+		v.Required(*f.Content, "content")
+		v.MaxSize(*f.Content, 65535, "content")
+	}
+	if f.CoverImageUrl != nil {
+		v.Required(*f.CoverImageUrl, "cover_image_url")
+		v.MaxSize(*f.CoverImageUrl, 255, "cover_image_url")
+	}
+	if f.State != nil {
+		v.Range(*f.State, 0, 1, "state")
+	}
+	if f.Visibility != nil {
+		validVisibility(v, *f.Visibility)
+	}
+	if f.Password != nil {
+		v.Required(*f.Password, "password")
+		v.MaxSize(*f.Password, 100, "password")
+	}
+	if f.Lang != nil {
+		validLang(v, *f.Lang)
+	}
+	if f.ModifiedBy != nil {
+		v.Required(*f.ModifiedBy, "modified_by")
+		v.MaxSize(*f.ModifiedBy, 100, "modified_by")
+	}
+}
+
+// @Summary Partially update article with a JSON Merge Patch
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID"
+// @Param tag_id body int false "TagID"
+// @Param title body string false "Title"
+// @Param slug body string false "Slug"
+// @Param desc body string false "Desc"
+// @Param content body string false "Content"
+// @Param cover_image_url body string false "CoverImageUrl"
+// @Param state body int false "State"
+// @Param visibility body string false "Visibility"
+// @Param password body string false "Password"
+// @Param lang body string false "Lang"
+// @Param modified_by body string false "ModifiedBy"
+// @Param If-Match header string false "ETag"
+// @Success 200 {object} app.Response
+// @Failure 412 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/articles/{id} [patch]
+func PatchArticle(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form PatchArticleForm
+	)
+
+	id := com.StrTo(c.Param("id")).MustInt()
+	valid := validation.Validation{}
+	valid.Min(id, 1, "id")
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	fields, httpCode, errCode := app.BindMergePatch(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	articleService := article_service.Article{ID: id}
+	if !bindIfMatch(c, &articleService) {
+		appG.Response(http.StatusPreconditionFailed, e.ERROR_ARTICLE_PRECONDITION_FAILED, nil)
+		return
+	}
+
+	exists, err := articleService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE, nil)
+		return
+	}
+	if !checkArticleEdit(appG, &articleService) {
+		return
+	}
+
+	if form.TagID != nil {
+		tagService := tag_service.Tag{ID: *form.TagID}
+		exists, err = tagService.ExistByID()
+		if err != nil {
+			appG.Response(http.StatusInternalServerError, e.ERROR_EXIST_TAG_FAIL, nil)
+			return
+		}
+		if !exists {
+			appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_TAG, nil)
+			return
+		}
+		articleService.TagID = *form.TagID
+	}
+	if form.Title != nil {
+		articleService.Title = *form.Title
+	}
+	if form.Slug != nil {
+		articleService.Slug = *form.Slug
+		exists, err = articleService.ExistBySlug()
+		if err != nil {
+			appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
+			return
+		}
+		if exists {
+			appG.Response(http.StatusOK, e.ERROR_EXIST_ARTICLE_SLUG, nil)
+			return
+		}
+	}
+	if form.Desc != nil {
+		articleService.Desc = *form.Desc
+	}
+	if form.Content != nil {
+		articleService.Content = *form.Content
+	}
+	if form.CoverImageUrl != nil {
+		articleService.CoverImageUrl = *form.CoverImageUrl
+	}
+	if form.State != nil {
+		articleService.State = *form.State
+	}
+	if form.Visibility != nil {
+		articleService.Visibility = *form.Visibility
+	}
+	if form.Password != nil {
+		articleService.Password = *form.Password
+	}
+	if form.Lang != nil {
+		articleService.Lang = *form.Lang
+	}
+	if form.ModifiedBy != nil {
+		articleService.ModifiedBy = *form.ModifiedBy
+	}
+
+	err = articleService.EditFields(fields)
+	if err == article_service.ErrPreconditionFailed {
+		appG.Response(http.StatusPreconditionFailed, e.ERROR_ARTICLE_PRECONDITION_FAILED, nil)
+		return
+	}
+	if err == article_service.ErrPasswordNotSet {
+		appG.Response(http.StatusBadRequest, e.ERROR_ARTICLE_PASSWORD_NOT_SET, nil)
+		return
+	}
 	if err != nil {
 		appG.Response(http.StatusInternalServerError, e.ERROR_EDIT_ARTICLE_FAIL, nil)
 		return
@@ -252,7 +640,9 @@ func EditArticle(c *gin.Context) {
 // @Summary Delete article
 // @Produce  json
 // @Param id path int true "ID"
+// @Param If-Match header string false "ETag"
 // @Success 200 {object} app.Response
+// @Failure 412 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Router /api/v1/articles/{id} [delete]
 func DeleteArticle(c *gin.Context) {
@@ -268,6 +658,11 @@ func DeleteArticle(c *gin.Context) {
 	}
 
 	articleService := article_service.Article{ID: id}
+	if !bindIfMatch(c, &articleService) {
+		appG.Response(http.StatusPreconditionFailed, e.ERROR_ARTICLE_PRECONDITION_FAILED, nil)
+		return
+	}
+
 	exists, err := articleService.ExistByID()
 	if err != nil {
 		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
@@ -277,8 +672,15 @@ func DeleteArticle(c *gin.Context) {
 		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE, nil)
 		return
 	}
+	if !checkArticleEdit(appG, &articleService) {
+		return
+	}
 
 	err = articleService.Delete()
+	if err == article_service.ErrPreconditionFailed {
+		appG.Response(http.StatusPreconditionFailed, e.ERROR_ARTICLE_PRECONDITION_FAILED, nil)
+		return
+	}
 	if err != nil {
 		appG.Response(http.StatusInternalServerError, e.ERROR_DELETE_ARTICLE_FAIL, nil)
 		return
@@ -287,39 +689,356 @@ func DeleteArticle(c *gin.Context) {
 	appG.Response(http.StatusOK, e.SUCCESS, nil)
 }
 
-const (
-	QRCODE_URL = "https://github.com/EDDYCJY/blog#gin%E7%B3%BB%E5%88%97%E7%9B%AE%E5%BD%95"
-)
+type UnlockArticleForm struct {
+	ID       int    `form:"id" json:"id" valid:"Required;Min(1)"`
+	Password string `form:"password" json:"password" valid:"Required;MaxSize(100)"`
+}
+
+// @Summary Unlock a password protected article
+// @Produce  json
+// @Param id body int true "ID"
+// @Param password body string true "Password"
+// @Success 200 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/articles/unlock [post]
+func UnlockArticle(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form UnlockArticleForm
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
 
+	articleService := article_service.Article{ID: form.ID}
+	exists, err := articleService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE, nil)
+		return
+	}
+
+	token, expires, err := articleService.Unlock(form.Password)
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_ARTICLE_FAIL, nil)
+		return
+	}
+	if token == "" {
+		appG.Response(http.StatusForbidden, e.ERROR_ARTICLE_PASSWORD_WRONG, nil)
+		return
+	}
+
+	c.SetCookie(article_service.GetUnlockCookieName(form.ID), token,
+		int(setting.AppSetting.ArticleUnlockExpire/time.Second), "/", "", false, true)
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"expires": expires.Unix(),
+	})
+}
+
+// validVisibility checks that the visibility is one an article may have
+func validVisibility(v *validation.Validation, visibility string) {
+	switch visibility {
+	case models.VISIBILITY_PUBLIC, models.VISIBILITY_UNLISTED, models.VISIBILITY_PRIVATE, models.VISIBILITY_PASSWORD:
+	default:
+		v.SetError("visibility", "不支持的可见性")
+	}
+}
+
+// validLang checks that articles are published in the language
+func validLang(v *validation.Validation, lang string) {
+	if !util.IsLang(lang) {
+		v.SetError("lang", "不支持的语言")
+	}
+}
+
+// bindIfMatch reads the If-Match header into the version the article must still have,
+// false if the header does not match the article
+func bindIfMatch(c *gin.Context, a *article_service.Article) bool {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return true
+	}
+
+	version, ok := article_service.ParseIfMatch(a.ID, header)
+	a.Version = version
+
+	return ok
+}
+
+type BatchArticleForm struct {
+	IDs        []int  `form:"ids" json:"ids" valid:"Required;MaxSize(500)"`
+	Action     string `form:"action" json:"action" valid:"Required"`
+	TagID      int    `form:"tag_id" json:"tag_id"`
+	State      int    `form:"state" json:"state" valid:"Range(0,1)"`
+	ModifiedBy string `form:"modified_by" json:"modified_by" valid:"Required;MaxSize(100)"`
+}
+
+func (f *BatchArticleForm) Valid(v *validation.Validation) {
+	switch f.Action {
+	case article_service.BATCH_DELETE, article_service.BATCH_RESTORE, article_service.BATCH_SET_STATE:
+	case article_service.BATCH_RETAG:
+		v.Min(f.TagID, 1, "tag_id")
+	default:
+		v.SetError("action", "不支持的批量操作")
+	}
+}
+
+// @Summary Batch operate articles
+// @Produce  json
+// @Param ids body []int true "IDs"
+// @Param action body string true "Action: delete, restore, set_state or retag"
+// @Param tag_id body int false "TagID"
+// @Param state body int false "State"
+// @Param modified_by body string true "ModifiedBy"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/articles/batch [post]
+func BatchArticles(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form BatchArticleForm
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	if form.Action == article_service.BATCH_RETAG {
+		tagService := tag_service.Tag{ID: form.TagID}
+		exists, err := tagService.ExistByID()
+		if err != nil {
+			appG.Response(http.StatusInternalServerError, e.ERROR_EXIST_TAG_FAIL, nil)
+			return
+		}
+		if !exists {
+			appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_TAG, nil)
+			return
+		}
+	}
+
+	articleService := article_service.Article{
+		TagID:      form.TagID,
+		State:      form.State,
+		ModifiedBy: form.ModifiedBy,
+		Viewer:     article_service.NewViewer(app.GetClaims(c)),
+	}
+	results, err := articleService.Batch(form.IDs, form.Action)
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_BATCH_ARTICLE_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"results": results,
+	})
+}
+
+// @Summary Export articles
+// @Produce  json
+// @Param tag_id body int false "TagID"
+// @Param state body int false "State"
+// @Param format body string false "Format: markdown or xlsx"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/articles/export [post]
+func ExportArticle(c *gin.Context) {
+	appG := app.Gin{C: c}
+	valid := validation.Validation{}
+
+	state := -1
+	if arg := c.PostForm("state"); arg != "" {
+		state = com.StrTo(arg).MustInt()
+		valid.Range(state, 0, 1, "state")
+	}
+
+	tagId := -1
+	if arg := c.PostForm("tag_id"); arg != "" {
+		tagId = com.StrTo(arg).MustInt()
+		valid.Min(tagId, 1, "tag_id")
+	}
+
+	format := c.DefaultPostForm("format", article_service.FORMAT_MARKDOWN)
+	if format != article_service.FORMAT_MARKDOWN && format != article_service.FORMAT_XLSX {
+		valid.SetError("format", "不支持的导出格式")
+	}
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	articleService := article_service.Article{
+		TagID:  tagId,
+		State:  state,
+		Viewer: article_service.NewViewer(app.GetClaims(c)),
+	}
+	filename, err := export.NewName("articles")
+	if err == nil {
+		if format == article_service.FORMAT_XLSX {
+			filename, err = articleService.ExportExcel(filename, nil)
+		} else {
+			filename, err = articleService.ExportMarkdown(filename, nil)
+		}
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_EXPORT_ARTICLE_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]string{
+		"export_url":      export.GetExcelFullUrl(filename),
+		"export_save_url": export.GetExcelPath() + filename,
+	})
+}
+
+// @Summary Import articles
+// @Produce  json
+// @Param file body file true "Zip of markdown files with YAML front matter, or an Excel file"
+// @Param format body string false "Format: markdown or xlsx"
+// @Param mode body string false "Mode: create or upsert, markdown only"
+// @Param dry_run body bool false "DryRun"
+// @Param created_by body string true "CreatedBy"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/articles/import [post]
+func ImportArticle(c *gin.Context) {
+	appG := app.Gin{C: c}
+	valid := validation.Validation{}
+
+	format := c.DefaultPostForm("format", article_service.FORMAT_MARKDOWN)
+	if format != article_service.FORMAT_MARKDOWN && format != article_service.FORMAT_XLSX {
+		valid.SetError("format", "不支持的导入格式")
+	}
+
+	mode := c.DefaultPostForm("mode", article_service.IMPORT_MODE_CREATE)
+	if mode != article_service.IMPORT_MODE_CREATE && mode != article_service.IMPORT_MODE_UPSERT {
+		valid.SetError("mode", "不支持的导入模式")
+	}
+
+	createdBy := c.PostForm("created_by")
+	valid.Required(createdBy, "created_by")
+	valid.MaxSize(createdBy, 100, "created_by")
+
+	dryRun, _ := strconv.ParseBool(c.PostForm("dry_run"))
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+	defer file.Close()
+
+	articleService := article_service.Article{
+		CreatedBy: createdBy,
+		Viewer:    article_service.NewViewer(app.GetClaims(c)),
+	}
+	var results interface{}
+	if format == article_service.FORMAT_XLSX {
+		results, err = articleService.ImportExcel(file, dryRun)
+	} else {
+		results, err = articleService.ImportMarkdown(file, header.Size, mode, dryRun)
+	}
+	if err == article_service.ErrImportDuplicateSlug {
+		appG.Response(http.StatusBadRequest, e.ERROR_IMPORT_DUPLICATE_SLUG, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_IMPORT_ARTICLE_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"dry_run": dryRun,
+		"results": results,
+	})
+}
+
+// @Summary Generate the poster of an article, with a qr code linking to it
+// @Produce  json
+// @Param id path int true "ID"
+// @Param template body string false "Template"
+// @Success 200 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/articles/{id}/poster [post]
 func GenerateArticlePoster(c *gin.Context) {
 	appG := app.Gin{C: c}
-	article := &article_service.Article{}
-	qr := qrcode.NewQrCode(QRCODE_URL, 300, 300, qr.M, qr.Auto)
-	posterName := article_service.GetPosterFlag() + "-" + qrcode.GetQrCodeFileName(qr.URL) + qr.GetQrCodeExt()
-	articlePoster := article_service.NewArticlePoster(posterName, article, qr)
-	articlePosterBgService := article_service.NewArticlePosterBg(
-		"bg.jpg",
-		articlePoster,
-		&article_service.Rect{
-			X0: 0,
-			Y0: 0,
-			X1: 550,
-			Y1: 700,
-		},
-		&article_service.Pt{
-			X: 125,
-			Y: 298,
-		},
-	)
+	id := com.StrTo(c.Param("id")).MustInt()
+	templateName := c.DefaultPostForm("template", setting.AppSetting.PosterTemplate)
+	valid := validation.Validation{}
+	valid.Min(id, 1, "id")
+	if !poster.IsTemplateName(templateName) {
+		valid.SetError("template", "模板名称只能包含字母、数字和下划线")
+	}
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	articleService := article_service.Article{ID: id}
+	exists, err := articleService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE, nil)
+		return
+	}
+
+	article, err := articleService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_ARTICLE_FAIL, nil)
+		return
+	}
+	if !checkArticleAccess(appG, article) {
+		return
+	}
+
+	template, err := poster.GetTemplate(templateName)
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_POSTER_TEMPLATES_FAIL, nil)
+		return
+	}
+	if template == nil {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_POSTER_TEMPLATE, nil)
+		return
+	}
 
-	_, filePath, err := articlePosterBgService.Generate()
+	articlePoster := article_service.NewArticlePoster(article, template)
+	filePath, err := articlePoster.Generate()
 	if err != nil {
+		logging.Warn(err)
 		appG.Response(http.StatusInternalServerError, e.ERROR_GEN_ARTICLE_POSTER_FAIL, nil)
 		return
 	}
 
 	appG.Response(http.StatusOK, e.SUCCESS, map[string]string{
-		"poster_url":      qrcode.GetQrCodeFullUrl(posterName),
-		"poster_save_url": filePath + posterName,
+		"poster_url":      qrcode.GetQrCodeFullUrl(articlePoster.PosterName),
+		"poster_save_url": filePath + articlePoster.PosterName,
 	})
 }
@@ -0,0 +1,252 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+	"github.com/EDDYCJY/go-gin-example/service/author_service"
+)
+
+// @Summary Get a single author profile
+// @Produce  json
+// @Param id path int true "ID"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/authors/{id} [get]
+func GetAuthor(c *gin.Context) {
+	appG := app.Gin{C: c}
+	id := com.StrTo(c.Param("id")).MustInt()
+	valid := validation.Validation{}
+	valid.Min(id, 1, "id")
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	authorService := author_service.Author{ID: id}
+	exists, err := authorService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_AUTHOR_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_AUTHOR, nil)
+		return
+	}
+
+	author, err := authorService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_AUTHOR_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, author)
+}
+
+// @Summary Get the articles of an author
+// @Produce  json
+// @Param id path int true "ID"
+// @Param state query int false "State"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/authors/{id}/articles [get]
+func GetAuthorArticles(c *gin.Context) {
+	appG := app.Gin{C: c}
+	id := com.StrTo(c.Param("id")).MustInt()
+	valid := validation.Validation{}
+	valid.Min(id, 1, "id")
+
+	state := -1
+	if arg := c.Query("state"); arg != "" {
+		state = com.StrTo(arg).MustInt()
+		valid.Range(state, 0, 1, "state")
+	}
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	authorService := author_service.Author{ID: id}
+	exists, err := authorService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_AUTHOR_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_AUTHOR, nil)
+		return
+	}
+
+	author, err := authorService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_AUTHOR_FAIL, nil)
+		return
+	}
+
+	articleService := article_service.Article{
+		TagID:     -1,
+		State:     state,
+		CreatedBy: author.Username,
+		Viewer:    article_service.NewViewer(app.GetClaims(c)),
+		PageNum:   util.GetPage(c),
+		PageSize:  setting.AppSetting.PageSize,
+	}
+
+	total, err := articleService.Count()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_COUNT_ARTICLE_FAIL, nil)
+		return
+	}
+
+	articles, err := articleService.GetAll()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_ARTICLES_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": articles,
+		"total": total,
+	})
+}
+
+type AddAuthorForm struct {
+	AuthID      int    `form:"auth_id" json:"auth_id" valid:"Required;Min(1)"`
+	DisplayName string `form:"display_name" json:"display_name" valid:"Required;MaxSize(100)"`
+	Bio         string `form:"bio" json:"bio" valid:"MaxSize(1000)"`
+	AvatarUrl   string `form:"avatar_url" json:"avatar_url" valid:"MaxSize(255)"`
+	CreatedBy   string `form:"created_by" json:"created_by" valid:"Required;MaxSize(100)"`
+}
+
+// @Summary Add the author profile of an account
+// @Produce  json
+// @Param auth_id body int true "AuthID"
+// @Param display_name body string true "DisplayName"
+// @Param bio body string false "Bio"
+// @Param avatar_url body string false "AvatarUrl, an image saved by the upload endpoint"
+// @Param created_by body string true "CreatedBy"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/authors [post]
+func AddAuthor(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form AddAuthorForm
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	authorService := author_service.Author{
+		AuthID:      form.AuthID,
+		DisplayName: form.DisplayName,
+		Bio:         form.Bio,
+		AvatarUrl:   form.AvatarUrl,
+		CreatedBy:   form.CreatedBy,
+	}
+	exists, err := authorService.ExistByAuthID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_AUTHOR_FAIL, nil)
+		return
+	}
+	if exists {
+		appG.Response(http.StatusOK, e.ERROR_EXIST_AUTHOR, nil)
+		return
+	}
+
+	err = authorService.Add()
+	if err == author_service.ErrAuthNotFound {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_AUTH, nil)
+		return
+	}
+	if err == author_service.ErrAvatarNotUploaded {
+		appG.Response(http.StatusBadRequest, e.ERROR_AUTHOR_AVATAR_INVALID, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_ADD_AUTHOR_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]int{
+		"id": authorService.ID,
+	})
+}
+
+type EditAuthorForm struct {
+	ID          int    `form:"id" json:"id" valid:"Required;Min(1)"`
+	DisplayName string `form:"display_name" json:"display_name" valid:"Required;MaxSize(100)"`
+	Bio         string `form:"bio" json:"bio" valid:"MaxSize(1000)"`
+	AvatarUrl   string `form:"avatar_url" json:"avatar_url" valid:"MaxSize(255)"`
+	ModifiedBy  string `form:"modified_by" json:"modified_by" valid:"Required;MaxSize(100)"`
+}
+
+// @Summary Update an author profile
+// @Produce  json
+// @Param id path int true "ID"
+// @Param display_name body string true "DisplayName"
+// @Param bio body string false "Bio"
+// @Param avatar_url body string false "AvatarUrl, an image saved by the upload endpoint"
+// @Param modified_by body string true "ModifiedBy"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/authors/{id} [put]
+func EditAuthor(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form = EditAuthorForm{ID: com.StrTo(c.Param("id")).MustInt()}
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	authorService := author_service.Author{
+		ID:          form.ID,
+		DisplayName: form.DisplayName,
+		Bio:         form.Bio,
+		AvatarUrl:   form.AvatarUrl,
+		ModifiedBy:  form.ModifiedBy,
+	}
+	exists, err := authorService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_AUTHOR_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_AUTHOR, nil)
+		return
+	}
+
+	err = authorService.Edit()
+	if err == author_service.ErrAvatarNotUploaded {
+		appG.Response(http.StatusBadRequest, e.ERROR_AUTHOR_AVATAR_INVALID, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_EDIT_AUTHOR_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
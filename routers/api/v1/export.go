@@ -0,0 +1,137 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/export"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+	"github.com/EDDYCJY/go-gin-example/service/export_service"
+	"github.com/EDDYCJY/go-gin-example/service/tag_service"
+)
+
+// @Summary Start an export job, written in the background
+// @Produce  json
+// @Param type body string true "Type: tag or article"
+// @Param format body string false "Format: xlsx, csv or json for tags, markdown or xlsx for articles"
+// @Param name body string false "Name, tags only"
+// @Param lang body string false "Lang of the column titles, tags only"
+// @Param tag_id body int false "TagID, articles only"
+// @Param state body int false "State"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/exports [post]
+func StartExport(c *gin.Context) {
+	appG := app.Gin{C: c}
+	valid := validation.Validation{}
+
+	state := -1
+	if arg := c.PostForm("state"); arg != "" {
+		state = com.StrTo(arg).MustInt()
+		valid.Range(state, 0, 1, "state")
+	}
+
+	var (
+		typ    = c.PostForm("type")
+		format string
+		task   export_service.Task
+	)
+	switch typ {
+	case export_service.TYPE_TAG:
+		format = c.DefaultPostForm("format", export.FORMAT_XLSX)
+		tagFormat, ok := export.GetFormat(format)
+		if !ok {
+			valid.SetError("format", "不支持的导出格式")
+		}
+
+		lang := c.DefaultPostForm("lang", util.GetDefaultLang())
+		validLang(&valid, lang)
+
+		tagService := tag_service.Tag{
+			Name:  c.PostForm("name"),
+			State: state,
+		}
+		task = func(name string, progress export.Progress) (string, error) {
+			return tagService.Export(name, tagFormat, lang, progress)
+		}
+	case export_service.TYPE_ARTICLE:
+		format = c.DefaultPostForm("format", article_service.FORMAT_MARKDOWN)
+		if format != article_service.FORMAT_MARKDOWN && format != article_service.FORMAT_XLSX {
+			valid.SetError("format", "不支持的导出格式")
+		}
+
+		tagId := -1
+		if arg := c.PostForm("tag_id"); arg != "" {
+			tagId = com.StrTo(arg).MustInt()
+			valid.Min(tagId, 1, "tag_id")
+		}
+
+		articleService := article_service.Article{
+			TagID:  tagId,
+			State:  state,
+			Viewer: article_service.NewViewer(app.GetClaims(c)),
+		}
+		task = func(name string, progress export.Progress) (string, error) {
+			if format == article_service.FORMAT_XLSX {
+				return articleService.ExportExcel(name, progress)
+			}
+			return articleService.ExportMarkdown(name, progress)
+		}
+	default:
+		valid.SetError("type", "不支持的导出类型")
+	}
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	job, err := export_service.Start(typ, format, task)
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_START_EXPORT_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, job)
+}
+
+// @Summary Get the status and progress of an export job, with its download url once done
+// @Produce  json
+// @Param id path string true "ID"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/exports/{id} [get]
+func GetExport(c *gin.Context) {
+	appG := app.Gin{C: c}
+	id := c.Param("id")
+	valid := validation.Validation{}
+	valid.Required(id, "id")
+	valid.AlphaNumeric(id, "id")
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	job, err := export_service.Get(id)
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_EXPORT_FAIL, nil)
+		return
+	}
+	if job == nil {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_EXPORT, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, job)
+}
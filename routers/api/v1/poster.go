@@ -0,0 +1,35 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/poster"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+// @Summary Get the poster templates with their layers, to preview them
+// @Produce  json
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/posters/templates [get]
+func GetPosterTemplates(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	templates, err := poster.GetTemplates()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_POSTER_TEMPLATES_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists":   templates,
+		"total":   len(templates),
+		"default": setting.AppSetting.PosterTemplate,
+	})
+}
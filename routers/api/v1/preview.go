@@ -0,0 +1,78 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+)
+
+type AddPreviewForm struct {
+	ArticleID int `form:"article_id" json:"article_id" valid:"Required;Min(1)"`
+	Expire    int `form:"expire" json:"expire" valid:"Range(0,720)"`
+}
+
+// @Summary Sign a preview link of an article its author or an editor manages, valid until it expires or the article is edited
+// @Produce  json
+// @Param article_id body int true "ArticleID"
+// @Param expire body int false "Hours the link stays valid, the configured default if 0"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/previews [post]
+func AddPreview(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form AddPreviewForm
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	articleService := article_service.Article{ID: form.ArticleID}
+	exists, err := articleService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE, nil)
+		return
+	}
+
+	article, err := articleService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_ARTICLE_FAIL, nil)
+		return
+	}
+	// only those who can manage the article may share it before it is published
+	if !article_service.NewViewer(app.GetClaims(c)).CanManage(article) {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE, nil)
+		return
+	}
+
+	expire := setting.AppSetting.PreviewExpire
+	if form.Expire > 0 {
+		expire = time.Duration(form.Expire) * time.Hour
+	}
+
+	previewUrl, expires, err := articleService.SignPreview(expire)
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_SIGN_PREVIEW_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"preview_url": previewUrl,
+		"expires":     expires.Unix(),
+	})
+}
@@ -0,0 +1,321 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/series_service"
+)
+
+// @Summary Get multiple article series
+// @Produce  json
+// @Param name query string false "Name"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/series [get]
+func GetSeriesList(c *gin.Context) {
+	appG := app.Gin{C: c}
+	seriesService := series_service.Series{
+		Name:     c.Query("name"),
+		PageNum:  util.GetPage(c),
+		PageSize: setting.AppSetting.PageSize,
+	}
+
+	series, err := seriesService.GetAll()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_SERIES_FAIL, nil)
+		return
+	}
+
+	count, err := seriesService.Count()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_COUNT_SERIES_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": series,
+		"total": count,
+	})
+}
+
+// @Summary Get a single article series with its articles in order
+// @Produce  json
+// @Param id path int true "ID"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/series/{id} [get]
+func GetSeries(c *gin.Context) {
+	appG := app.Gin{C: c}
+	id := com.StrTo(c.Param("id")).MustInt()
+	valid := validation.Validation{}
+	valid.Min(id, 1, "id")
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	seriesService := series_service.Series{ID: id}
+	exists, err := seriesService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_SERIES_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_SERIES, nil)
+		return
+	}
+
+	series, err := seriesService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_SERIES_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, series)
+}
+
+type AddSeriesForm struct {
+	Name       string `form:"name" json:"name" valid:"Required;MaxSize(100)"`
+	Desc       string `form:"desc" json:"desc" valid:"MaxSize(255)"`
+	CreatedBy  string `form:"created_by" json:"created_by" valid:"Required;MaxSize(100)"`
+	ArticleIDs []int  `form:"article_ids" json:"article_ids" valid:"MaxSize(500)"`
+}
+
+func (f *AddSeriesForm) Valid(v *validation.Validation) {
+	validSeriesArticles(v, f.ArticleIDs)
+}
+
+// @Summary Add article series
+// @Produce  json
+// @Param name body string true "Name"
+// @Param desc body string false "Desc"
+// @Param created_by body string true "CreatedBy"
+// @Param article_ids body []int false "ArticleIDs, in the series order"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/series [post]
+func AddSeries(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form AddSeriesForm
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	seriesService := series_service.Series{
+		Name:       form.Name,
+		Desc:       form.Desc,
+		CreatedBy:  form.CreatedBy,
+		ArticleIDs: form.ArticleIDs,
+	}
+	exists, err := seriesService.ExistByName()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_SERIES_FAIL, nil)
+		return
+	}
+	if exists {
+		appG.Response(http.StatusOK, e.ERROR_EXIST_SERIES, nil)
+		return
+	}
+
+	err = seriesService.Add()
+	if code := getSeriesArticlesCode(err); code != e.SUCCESS {
+		appG.Response(http.StatusOK, code, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_ADD_SERIES_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+type EditSeriesForm struct {
+	ID         int    `form:"id" valid:"Required;Min(1)"`
+	Name       string `form:"name" json:"name" valid:"Required;MaxSize(100)"`
+	Desc       string `form:"desc" json:"desc" valid:"MaxSize(255)"`
+	ModifiedBy string `form:"modified_by" json:"modified_by" valid:"Required;MaxSize(100)"`
+}
+
+// @Summary Update article series
+// @Produce  json
+// @Param id path int true "ID"
+// @Param name body string true "Name"
+// @Param desc body string false "Desc"
+// @Param modified_by body string true "ModifiedBy"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/series/{id} [put]
+func EditSeries(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form = EditSeriesForm{ID: com.StrTo(c.Param("id")).MustInt()}
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	seriesService := series_service.Series{
+		ID:         form.ID,
+		Name:       form.Name,
+		Desc:       form.Desc,
+		ModifiedBy: form.ModifiedBy,
+	}
+	exists, err := seriesService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_SERIES_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_SERIES, nil)
+		return
+	}
+
+	err = seriesService.Edit()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_EDIT_SERIES_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+type EditSeriesArticlesForm struct {
+	ID         int    `form:"id" valid:"Required;Min(1)"`
+	ArticleIDs []int  `form:"article_ids" json:"article_ids" valid:"MaxSize(500)"`
+	ModifiedBy string `form:"modified_by" json:"modified_by" valid:"Required;MaxSize(100)"`
+}
+
+func (f *EditSeriesArticlesForm) Valid(v *validation.Validation) {
+	validSeriesArticles(v, f.ArticleIDs)
+}
+
+// @Summary Set the articles of a series and their order
+// @Produce  json
+// @Param id path int true "ID"
+// @Param article_ids body []int true "ArticleIDs, in the series order"
+// @Param modified_by body string true "ModifiedBy"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/series/{id}/articles [put]
+func EditSeriesArticles(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form = EditSeriesArticlesForm{ID: com.StrTo(c.Param("id")).MustInt()}
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	seriesService := series_service.Series{
+		ID:         form.ID,
+		ArticleIDs: form.ArticleIDs,
+		ModifiedBy: form.ModifiedBy,
+	}
+	exists, err := seriesService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_SERIES_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_SERIES, nil)
+		return
+	}
+
+	err = seriesService.EditArticles()
+	if code := getSeriesArticlesCode(err); code != e.SUCCESS {
+		appG.Response(http.StatusOK, code, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_EDIT_SERIES_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// @Summary Delete article series
+// @Produce  json
+// @Param id path int true "ID"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/series/{id} [delete]
+func DeleteSeries(c *gin.Context) {
+	appG := app.Gin{C: c}
+	valid := validation.Validation{}
+	id := com.StrTo(c.Param("id")).MustInt()
+	valid.Min(id, 1, "id").Message("ID必须大于0")
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	seriesService := series_service.Series{ID: id}
+	exists, err := seriesService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_SERIES_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_SERIES, nil)
+		return
+	}
+
+	if err := seriesService.Delete(); err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_DELETE_SERIES_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// validSeriesArticles checks the members of a series, each article may only appear once
+func validSeriesArticles(v *validation.Validation, ids []int) {
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if id < 1 || seen[id] {
+			v.SetError("article_ids", "文章ID必须大于0且不能重复")
+			return
+		}
+		seen[id] = true
+	}
+}
+
+// getSeriesArticlesCode get the error code of a series member failure, SUCCESS for any other error
+func getSeriesArticlesCode(err error) int {
+	switch err {
+	case series_service.ErrArticleNotFound:
+		return e.ERROR_NOT_EXIST_SERIES_ARTICLE
+	case series_service.ErrArticleInOtherSeries:
+		return e.ERROR_SERIES_ARTICLE_IN_OTHER
+	}
+
+	return e.SUCCESS
+}
@@ -1,7 +1,9 @@
 package v1
 
 import (
+	"mime/multipart"
 	"net/http"
+	"strconv"
 
 	"github.com/unknwon/com"
 	"github.com/astaxie/beego/validation"
@@ -56,14 +58,26 @@ func GetTags(c *gin.Context) {
 }
 
 type AddTagForm struct {
-	Name      string `form:"name" valid:"Required;MaxSize(100)"`
-	CreatedBy string `form:"created_by" valid:"Required;MaxSize(100)"`
-	State     int    `form:"state" valid:"Range(0,1)"`
+	Name          string `form:"name" valid:"Required;MaxSize(100)"`
+	Description   string `form:"description" valid:"MaxSize(255)"`
+	Color         string `form:"color"`
+	CoverImageUrl string `form:"cover_image_url" valid:"MaxSize(255)"`
+	SortOrder     int    `form:"sort_order" valid:"Min(0)"`
+	CreatedBy     string `form:"created_by" valid:"Required;MaxSize(100)"`
+	State         int    `form:"state" valid:"Range(0,1)"`
+}
+
+func (f *AddTagForm) Valid(v *validation.Validation) {
+	validColor(v, f.Color)
 }
 
 // @Summary Add article tag
 // @Produce  json
 // @Param name body string true "Name"
+// @Param description body string false "Description"
+// @Param color body string false "Color, written as #RRGGBB"
+// @Param cover_image_url body string false "CoverImageUrl, an image saved by the upload endpoint"
+// @Param sort_order body int false "SortOrder"
 // @Param state body int false "State"
 // @Param created_by body int false "CreatedBy"
 // @Success 200 {object} app.Response
@@ -82,9 +96,13 @@ func AddTag(c *gin.Context) {
 	}
 
 	tagService := tag_service.Tag{
-		Name:      form.Name,
-		CreatedBy: form.CreatedBy,
-		State:     form.State,
+		Name:          form.Name,
+		Description:   form.Description,
+		Color:         form.Color,
+		CoverImageUrl: form.CoverImageUrl,
+		SortOrder:     form.SortOrder,
+		CreatedBy:     form.CreatedBy,
+		State:         form.State,
 	}
 	exists, err := tagService.ExistByName()
 	if err != nil {
@@ -97,6 +115,10 @@ func AddTag(c *gin.Context) {
 	}
 
 	err = tagService.Add()
+	if err == tag_service.ErrCoverNotUploaded {
+		appG.Response(http.StatusBadRequest, e.ERROR_TAG_COVER_INVALID, nil)
+		return
+	}
 	if err != nil {
 		appG.Response(http.StatusInternalServerError, e.ERROR_ADD_TAG_FAIL, nil)
 		return
@@ -106,16 +128,28 @@ func AddTag(c *gin.Context) {
 }
 
 type EditTagForm struct {
-	ID         int    `form:"id" valid:"Required;Min(1)"`
-	Name       string `form:"name" valid:"Required;MaxSize(100)"`
-	ModifiedBy string `form:"modified_by" valid:"Required;MaxSize(100)"`
-	State      int    `form:"state" valid:"Range(0,1)"`
+	ID            int    `form:"id" valid:"Required;Min(1)"`
+	Name          string `form:"name" valid:"Required;MaxSize(100)"`
+	Description   string `form:"description" valid:"MaxSize(255)"`
+	Color         string `form:"color"`
+	CoverImageUrl string `form:"cover_image_url" valid:"MaxSize(255)"`
+	SortOrder     int    `form:"sort_order" valid:"Min(0)"`
+	ModifiedBy    string `form:"modified_by" valid:"Required;MaxSize(100)"`
+	State         int    `form:"state" valid:"Range(0,1)"`
+}
+
+func (f *EditTagForm) Valid(v *validation.Validation) {
+	validColor(v, f.Color)
 }
 
 // @Summary Update article tag
 // @Produce  json
 // @Param id path int true "ID"
 // @Param name body string true "Name"
+// @Param description body string false "Description"
+// @Param color body string false "Color, written as #RRGGBB"
+// @Param cover_image_url body string false "CoverImageUrl, an image saved by the upload endpoint"
+// @Param sort_order body int false "SortOrder"
 // @Param state body int false "State"
 // @Param modified_by body string true "ModifiedBy"
 // @Success 200 {object} app.Response
@@ -134,10 +168,14 @@ func EditTag(c *gin.Context) {
 	}
 
 	tagService := tag_service.Tag{
-		ID:         form.ID,
-		Name:       form.Name,
-		ModifiedBy: form.ModifiedBy,
-		State:      form.State,
+		ID:            form.ID,
+		Name:          form.Name,
+		Description:   form.Description,
+		Color:         form.Color,
+		CoverImageUrl: form.CoverImageUrl,
+		SortOrder:     form.SortOrder,
+		ModifiedBy:    form.ModifiedBy,
+		State:         form.State,
 	}
 
 	exists, err := tagService.ExistByID()
@@ -152,6 +190,10 @@ func EditTag(c *gin.Context) {
 	}
 
 	err = tagService.Edit()
+	if err == tag_service.ErrCoverNotUploaded {
+		appG.Response(http.StatusBadRequest, e.ERROR_TAG_COVER_INVALID, nil)
+		return
+	}
 	if err != nil {
 		appG.Response(http.StatusInternalServerError, e.ERROR_EDIT_TAG_FAIL, nil)
 		return
@@ -160,11 +202,132 @@ func EditTag(c *gin.Context) {
 	appG.Response(http.StatusOK, e.SUCCESS, nil)
 }
 
-// @Summary Delete article tag
+type PatchTagForm struct {
+	Name          *string `json:"name"`
+	Description   *string `json:"description"`
+	Color         *string `json:"color"`
+	CoverImageUrl *string `json:"cover_image_url"`
+	SortOrder     *int    `json:"sort_order"`
+	State         *int    `json:"state"`
+	ModifiedBy    *string `json:"modified_by"`
+}
+
+func (f *PatchTagForm) Valid(v *validation.Validation) {
+	if f.Name != nil {
+		v.Required(*f.Name, "name")
+		v.MaxSize(*f.Name, 100, "name")
+	}
+	if f.Description != nil {
+		v.MaxSize(*f.Description, 255, "description")
+	}
+	if f.Color != nil {
+		validColor(v, *f.Color)
+	}
+	if f.CoverImageUrl != nil {
+		v.MaxSize(*f.CoverImageUrl, 255, "cover_image_url")
+	}
+	if f.SortOrder != nil {
+		v.Min(*f.SortOrder, 0, "sort_order")
+	}
+	if f.State != nil {
+		v.Range(*f.State, 0, 1, "state")
+	}
+	if f.ModifiedBy != nil {
+		v.Required(*f.ModifiedBy, "modified_by")
+		v.MaxSize(*f.ModifiedBy, 100, "modified_by")
+	}
+}
+
+// @Summary Partially update article tag with a JSON Merge Patch
+// @Accept  json
 // @Produce  json
 // @Param id path int true "ID"
+// @Param name body string false "Name"
+// @Param description body string false "Description"
+// @Param color body string false "Color, written as #RRGGBB"
+// @Param cover_image_url body string false "CoverImageUrl, an image saved by the upload endpoint"
+// @Param sort_order body int false "SortOrder"
+// @Param state body int false "State"
+// @Param modified_by body string false "ModifiedBy"
 // @Success 200 {object} app.Response
 // @Failure 500 {object} app.Response
+// @Router /api/v1/tags/{id} [patch]
+func PatchTag(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form PatchTagForm
+	)
+
+	id := com.StrTo(c.Param("id")).MustInt()
+	valid := validation.Validation{}
+	valid.Min(id, 1, "id")
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	fields, httpCode, errCode := app.BindMergePatch(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	tagService := tag_service.Tag{ID: id, State: -1}
+	exists, err := tagService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_EXIST_TAG_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_TAG, nil)
+		return
+	}
+
+	if form.Name != nil {
+		tagService.Name = *form.Name
+	}
+	if form.Description != nil {
+		tagService.Description = *form.Description
+	}
+	if form.Color != nil {
+		tagService.Color = *form.Color
+	}
+	if form.CoverImageUrl != nil {
+		tagService.CoverImageUrl = *form.CoverImageUrl
+	}
+	if form.SortOrder != nil {
+		tagService.SortOrder = *form.SortOrder
+	}
+	if form.State != nil {
+		tagService.State = *form.State
+	}
+	if form.ModifiedBy != nil {
+		tagService.ModifiedBy = *form.ModifiedBy
+	}
+
+	err = tagService.EditFields(fields)
+	if err == tag_service.ErrCoverNotUploaded {
+		appG.Response(http.StatusBadRequest, e.ERROR_TAG_COVER_INVALID, nil)
+		return
+	}
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_EDIT_TAG_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// @Summary Delete article tag, refused while articles refer to it unless they are reassigned or untagged
+// @Produce  json
+// @Param id path int true "ID"
+// @Param strategy query string false "Strategy: refuse, reassign or untag"
+// @Param tag_id query int false "TagID the articles are reassigned to"
+// @Param modified_by query string false "ModifiedBy, required to reassign or untag"
+// @Success 200 {object} app.Response
+// @Failure 409 {object} app.Response
+// @Failure 500 {object} app.Response
 // @Router /api/v1/tags/{id} [delete]
 func DeleteTag(c *gin.Context) {
 	appG := app.Gin{C: c}
@@ -172,12 +335,28 @@ func DeleteTag(c *gin.Context) {
 	id := com.StrTo(c.Param("id")).MustInt()
 	valid.Min(id, 1, "id").Message("ID必须大于0")
 
+	strategy := c.DefaultQuery("strategy", tag_service.DELETE_REFUSE)
+	toID := com.StrTo(c.Query("tag_id")).MustInt()
+	modifiedBy := c.Query("modified_by")
+	switch strategy {
+	case tag_service.DELETE_REFUSE:
+	case tag_service.DELETE_REASSIGN:
+		valid.Min(toID, 1, "tag_id")
+		fallthrough
+	case tag_service.DELETE_UNTAG:
+		valid.Required(modifiedBy, "modified_by")
+		valid.MaxSize(modifiedBy, 100, "modified_by")
+	default:
+		valid.SetError("strategy", "不支持的删除策略")
+	}
+
 	if valid.HasErrors() {
 		app.MarkErrors(valid.Errors)
 		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
 	}
 
-	tagService := tag_service.Tag{ID: id}
+	tagService := tag_service.Tag{ID: id, ModifiedBy: modifiedBy}
 	exists, err := tagService.ExistByID()
 	if err != nil {
 		appG.Response(http.StatusInternalServerError, e.ERROR_EXIST_TAG_FAIL, nil)
@@ -189,36 +368,134 @@ func DeleteTag(c *gin.Context) {
 		return
 	}
 
-	if err := tagService.Delete(); err != nil {
+	count, err := tagService.Delete(strategy, toID)
+	if err == tag_service.ErrTagInUse {
+		appG.Response(http.StatusConflict, e.ERROR_TAG_IN_USE, map[string]int{
+			"articles": count,
+		})
+		return
+	}
+	if err == tag_service.ErrReassignNotFound {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_REASSIGN_TAG, nil)
+		return
+	}
+	if err == tag_service.ErrReassignToSelf {
+		appG.Response(http.StatusBadRequest, e.ERROR_REASSIGN_TAG_TO_ITSELF, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
 		appG.Response(http.StatusInternalServerError, e.ERROR_DELETE_TAG_FAIL, nil)
 		return
 	}
 
-	appG.Response(http.StatusOK, e.SUCCESS, nil)
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]int{
+		"articles": count,
+	})
+}
+
+// validColor allows an empty color, meaning the front end picks one
+func validColor(v *validation.Validation, color string) {
+	if color != "" && !tag_service.IsColor(color) {
+		v.SetError("color", "颜色必须为 #RRGGBB 格式")
+	}
+}
+
+type BatchTagForm struct {
+	IDs        []int  `form:"ids" json:"ids" valid:"Required;MaxSize(500)"`
+	Action     string `form:"action" json:"action" valid:"Required"`
+	State      int    `form:"state" json:"state" valid:"Range(0,1)"`
+	ModifiedBy string `form:"modified_by" json:"modified_by" valid:"Required;MaxSize(100)"`
+}
+
+func (f *BatchTagForm) Valid(v *validation.Validation) {
+	switch f.Action {
+	case tag_service.BATCH_DELETE, tag_service.BATCH_RESTORE, tag_service.BATCH_SET_STATE:
+	default:
+		v.SetError("action", "不支持的批量操作")
+	}
+}
+
+// @Summary Batch operate article tags
+// @Produce  json
+// @Param ids body []int true "IDs"
+// @Param action body string true "Action: delete, restore or set_state"
+// @Param state body int false "State"
+// @Param modified_by body string true "ModifiedBy"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/tags/batch [post]
+func BatchTags(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form BatchTagForm
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	tagService := tag_service.Tag{
+		State:      form.State,
+		ModifiedBy: form.ModifiedBy,
+	}
+	results, err := tagService.Batch(form.IDs, form.Action)
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_BATCH_TAG_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"results": results,
+	})
 }
 
 // @Summary Export article tag
 // @Produce  json
 // @Param name body string false "Name"
 // @Param state body int false "State"
+// @Param format body string false "Format: xlsx, csv or json"
+// @Param lang body string false "Lang of the column titles"
 // @Success 200 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Router /api/v1/tags/export [post]
 func ExportTag(c *gin.Context) {
 	appG := app.Gin{C: c}
+	valid := validation.Validation{}
 	name := c.PostForm("name")
 	state := -1
 	if arg := c.PostForm("state"); arg != "" {
 		state = com.StrTo(arg).MustInt()
 	}
 
+	format, ok := export.GetFormat(c.DefaultPostForm("format", export.FORMAT_XLSX))
+	if !ok {
+		valid.SetError("format", "不支持的导出格式")
+	}
+
+	lang := c.DefaultPostForm("lang", util.GetDefaultLang())
+	validLang(&valid, lang)
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
 	tagService := tag_service.Tag{
 		Name:  name,
 		State: state,
 	}
 
-	filename, err := tagService.Export()
+	filename, err := export.NewName("tags")
+	if err == nil {
+		filename, err = tagService.Export(filename, format, lang, nil)
+	}
 	if err != nil {
+		logging.Warn(err)
 		appG.Response(http.StatusInternalServerError, e.ERROR_EXPORT_TAG_FAIL, nil)
 		return
 	}
@@ -229,29 +506,72 @@ func ExportTag(c *gin.Context) {
 	})
 }
 
-// @Summary Import article tag
+// @Summary Import article tags, reporting the result of each row
 // @Produce  json
-// @Param file body file true "Excel File"
+// @Param file body file true "File in xlsx, csv or json"
+// @Param format body string false "Format, taken from the Content-Type or extension of the file by default"
+// @Param dry_run body bool false "DryRun"
+// @Param created_by body string false "CreatedBy, used for the rows without one"
 // @Success 200 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Router /api/v1/tags/import [post]
 func ImportTag(c *gin.Context) {
 	appG := app.Gin{C: c}
+	valid := validation.Validation{}
 
-	file, _, err := c.Request.FormFile("file")
+	createdBy := c.PostForm("created_by")
+	valid.MaxSize(createdBy, 100, "created_by")
+
+	dryRun, _ := strconv.ParseBool(c.PostForm("dry_run"))
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
 	if err != nil {
 		logging.Warn(err)
-		appG.Response(http.StatusInternalServerError, e.ERROR, nil)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+	defer file.Close()
+
+	format, ok := getImportFormat(c.PostForm("format"), header)
+	if !ok {
+		valid.SetError("format", "不支持的导入格式")
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
 		return
 	}
 
-	tagService := tag_service.Tag{}
-	err = tagService.Import(file)
+	tagService := tag_service.Tag{CreatedBy: createdBy}
+	results, err := tagService.Import(file, format, dryRun)
+	if err == tag_service.ErrImportNoName {
+		appG.Response(http.StatusBadRequest, e.ERROR_IMPORT_TAG_NO_NAME, nil)
+		return
+	}
 	if err != nil {
 		logging.Warn(err)
 		appG.Response(http.StatusInternalServerError, e.ERROR_IMPORT_TAG_FAIL, nil)
 		return
 	}
 
-	appG.Response(http.StatusOK, e.SUCCESS, nil)
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"dry_run": dryRun,
+		"results": results,
+	})
+}
+
+// getImportFormat picks the format of an uploaded file by name, then by the Content-Type or extension of the file
+func getImportFormat(name string, header *multipart.FileHeader) (export.Format, bool) {
+	if name != "" {
+		return export.GetFormat(name)
+	}
+	if format, ok := export.GetFormatByContentType(header.Header.Get("Content-Type")); ok {
+		return format, true
+	}
+
+	return export.GetFormatByFilename(header.Filename)
 }
@@ -0,0 +1,204 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/service/tag_service"
+)
+
+type MergeTagForm struct {
+	ID         int    `form:"id" json:"id" valid:"Required;Min(1)"`
+	SourceIDs  []int  `form:"source_ids" json:"source_ids" valid:"Required;MaxSize(100)"`
+	ModifiedBy string `form:"modified_by" json:"modified_by" valid:"Required;MaxSize(100)"`
+}
+
+// @Summary Merge tags into a tag, their articles move to it and their names become its aliases
+// @Produce  json
+// @Param id path int true "ID of the tag kept"
+// @Param source_ids body []int true "IDs of the tags merged and deleted"
+// @Param modified_by body string true "ModifiedBy"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/tags/{id}/merge [post]
+func MergeTag(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form = MergeTagForm{ID: com.StrTo(c.Param("id")).MustInt()}
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	tagService := tag_service.Tag{ID: form.ID, ModifiedBy: form.ModifiedBy}
+	exists, err := tagService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_EXIST_TAG_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_TAG, nil)
+		return
+	}
+
+	moved, err := tagService.Merge(form.SourceIDs)
+	if err == tag_service.ErrMergeTargetNotFound {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_TAG, nil)
+		return
+	}
+	if err == tag_service.ErrMergeSourceNotFound {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_MERGE_TAG, nil)
+		return
+	}
+	if err == tag_service.ErrMergeIntoSource {
+		appG.Response(http.StatusBadRequest, e.ERROR_MERGE_TAG_INTO_ITSELF, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_MERGE_TAG_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"id":       form.ID,
+		"merged":   form.SourceIDs,
+		"articles": moved,
+	})
+}
+
+// @Summary Get the aliases of a tag
+// @Produce  json
+// @Param id path int true "ID"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/tags/{id}/aliases [get]
+func GetTagAliases(c *gin.Context) {
+	appG := app.Gin{C: c}
+	id := com.StrTo(c.Param("id")).MustInt()
+	valid := validation.Validation{}
+	valid.Min(id, 1, "id")
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	tagService := tag_service.Tag{ID: id}
+	exists, err := tagService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_EXIST_TAG_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_TAG, nil)
+		return
+	}
+
+	aliases, err := tagService.GetAliases()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_TAG_ALIASES_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": aliases,
+	})
+}
+
+type AddTagAliasForm struct {
+	ID        int    `form:"id" json:"id" valid:"Required;Min(1)"`
+	Name      string `form:"name" json:"name" valid:"Required;MaxSize(100)"`
+	CreatedBy string `form:"created_by" json:"created_by" valid:"Required;MaxSize(100)"`
+}
+
+// @Summary Add an alias to a tag
+// @Produce  json
+// @Param id path int true "ID"
+// @Param name body string true "Name"
+// @Param created_by body string true "CreatedBy"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/tags/{id}/aliases [post]
+func AddTagAlias(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form = AddTagAliasForm{ID: com.StrTo(c.Param("id")).MustInt()}
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	tagService := tag_service.Tag{ID: form.ID, CreatedBy: form.CreatedBy}
+	exists, err := tagService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_EXIST_TAG_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_TAG, nil)
+		return
+	}
+
+	err = tagService.AddAlias(form.Name)
+	if err == tag_service.ErrAliasTaken {
+		appG.Response(http.StatusOK, e.ERROR_EXIST_TAG_ALIAS, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_ADD_TAG_ALIAS_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// @Summary Delete an alias of a tag
+// @Produce  json
+// @Param id path int true "ID"
+// @Param name path string true "Name"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/tags/{id}/aliases/{name} [delete]
+func DeleteTagAlias(c *gin.Context) {
+	appG := app.Gin{C: c}
+	id := com.StrTo(c.Param("id")).MustInt()
+	name := c.Param("name")
+	valid := validation.Validation{}
+	valid.Min(id, 1, "id")
+	valid.Required(name, "name")
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	tagService := tag_service.Tag{ID: id}
+	deleted, err := tagService.DeleteAlias(name)
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_DELETE_TAG_ALIAS_FAIL, nil)
+		return
+	}
+	if !deleted {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_TAG_ALIAS, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
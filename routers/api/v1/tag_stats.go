@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/service/tag_service"
+)
+
+// @Summary Get the tags with the number of their published articles and when they were last used
+// @Produce  json
+// @Param state query int false "State"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/tags/stats [get]
+func GetTagStats(c *gin.Context) {
+	appG := app.Gin{C: c}
+	tagService, ok := bindTagStats(c)
+	if !ok {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	stats, err := tagService.GetStats()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_TAG_STATS_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": stats,
+	})
+}
+
+// @Summary Get the tag cloud, the tags having published articles weighted between 0 and 1
+// @Produce  json
+// @Param state query int false "State"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/tags/cloud [get]
+func GetTagCloud(c *gin.Context) {
+	appG := app.Gin{C: c}
+	tagService, ok := bindTagStats(c)
+	if !ok {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	cloud, err := tagService.GetCloud()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_TAG_STATS_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": cloud,
+	})
+}
+
+func bindTagStats(c *gin.Context) (*tag_service.Tag, bool) {
+	valid := validation.Validation{}
+	state := -1
+	if arg := c.Query("state"); arg != "" {
+		state = com.StrTo(arg).MustInt()
+		valid.Range(state, 0, 1, "state")
+	}
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		return nil, false
+	}
+
+	return &tag_service.Tag{State: state}, true
+}
@@ -0,0 +1,185 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+)
+
+// @Summary Get the translations of an article
+// @Produce  json
+// @Param id path int true "ID"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/articles/{id}/translations [get]
+func GetArticleTranslations(c *gin.Context) {
+	appG := app.Gin{C: c}
+	id := com.StrTo(c.Param("id")).MustInt()
+	valid := validation.Validation{}
+	valid.Min(id, 1, "id")
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	articleService := article_service.Article{ID: id}
+	exists, err := articleService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE, nil)
+		return
+	}
+
+	article, err := articleService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_ARTICLE_FAIL, nil)
+		return
+	}
+	if !checkArticleAccess(appG, article) {
+		return
+	}
+
+	translations, err := articleService.GetTranslations()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_TRANSLATIONS_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": translations,
+	})
+}
+
+type SaveTranslationForm struct {
+	ID         int    `form:"id" valid:"Required;Min(1)"`
+	Lang       string `form:"lang"`
+	Title      string `form:"title" valid:"Required;MaxSize(100)"`
+	Desc       string `form:"desc" valid:"Required;MaxSize(255)"`
+	Content    string `form:"content" valid:"Required;MaxSize(65535)"`
+	ModifiedBy string `form:"modified_by" valid:"Required;MaxSize(100)"`
+}
+
+func (f *SaveTranslationForm) Valid(v *validation.Validation) {
+	validLang(v, f.Lang)
+}
+
+// @Summary Add or replace the translation of an article in a language
+// @Produce  json
+// @Param id path int true "ID"
+// @Param lang path string true "Lang"
+// @Param title body string true "Title"
+// @Param desc body string true "Desc"
+// @Param content body string true "Content"
+// @Param modified_by body string true "ModifiedBy"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/articles/{id}/translations/{lang} [put]
+func SaveArticleTranslation(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form = SaveTranslationForm{ID: com.StrTo(c.Param("id")).MustInt(), Lang: c.Param("lang")}
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	articleService := article_service.Article{ID: form.ID}
+	exists, err := articleService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE, nil)
+		return
+	}
+	if !checkArticleEdit(appG, &articleService) {
+		return
+	}
+
+	translation := article_service.Translation{
+		ArticleID:  form.ID,
+		Lang:       form.Lang,
+		Title:      form.Title,
+		Desc:       form.Desc,
+		Content:    form.Content,
+		ModifiedBy: form.ModifiedBy,
+	}
+	err = translation.Save()
+	if err == article_service.ErrSourceLang {
+		appG.Response(http.StatusBadRequest, e.ERROR_TRANSLATION_SOURCE_LANG, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_SAVE_TRANSLATION_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// @Summary Delete the translation of an article in a language
+// @Produce  json
+// @Param id path int true "ID"
+// @Param lang path string true "Lang"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v1/articles/{id}/translations/{lang} [delete]
+func DeleteArticleTranslation(c *gin.Context) {
+	appG := app.Gin{C: c}
+	id := com.StrTo(c.Param("id")).MustInt()
+	lang := c.Param("lang")
+	valid := validation.Validation{}
+	valid.Min(id, 1, "id")
+	validLang(&valid, lang)
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	articleService := article_service.Article{ID: id}
+	exists, err := articleService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE, nil)
+		return
+	}
+	if !checkArticleEdit(appG, &articleService) {
+		return
+	}
+
+	translation := article_service.Translation{ArticleID: id, Lang: lang}
+	deleted, err := translation.Delete()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_DELETE_TRANSLATION_FAIL, nil)
+		return
+	}
+	if !deleted {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_TRANSLATION, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
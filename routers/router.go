@@ -12,6 +12,7 @@ import (
 	"github.com/EDDYCJY/go-gin-example/middleware/jwt"
 	"github.com/EDDYCJY/go-gin-example/pkg/export"
 	"github.com/EDDYCJY/go-gin-example/pkg/qrcode"
+	"github.com/EDDYCJY/go-gin-example/pkg/sitemap"
 	"github.com/EDDYCJY/go-gin-example/pkg/upload"
 	"github.com/EDDYCJY/go-gin-example/routers/api"
 	"github.com/EDDYCJY/go-gin-example/routers/api/v1"
@@ -26,22 +27,56 @@ func InitRouter() *gin.Engine {
 	r.StaticFS("/export", http.Dir(export.GetExcelFullPath()))
 	r.StaticFS("/upload/images", http.Dir(upload.GetImageFullPath()))
 	r.StaticFS("/qrcode", http.Dir(qrcode.GetQrCodeFullPath()))
+	r.StaticFS("/sitemap", http.Dir(sitemap.GetSitemapFullPath()))
 
 	r.POST("/auth", api.GetAuth)
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	r.POST("/upload", api.UploadImage)
 
+	//订阅源
+	r.GET("/feed/rss.xml", api.GetRSSFeed)
+	r.GET("/feed/atom.xml", api.GetAtomFeed)
+	r.GET("/feed/tags/:id/rss.xml", api.GetRSSFeed)
+	r.GET("/feed/tags/:id/atom.xml", api.GetAtomFeed)
+
+	//站点地图
+	r.GET("/sitemap.xml", api.GetSitemap)
+	r.GET("/robots.txt", api.GetRobots)
+
+	//文章预览
+	r.GET("/preview/articles/:id", api.GetPreview)
+
 	apiv1 := r.Group("/api/v1")
 	apiv1.Use(jwt.JWT())
 	{
 		//获取标签列表
 		apiv1.GET("/tags", v1.GetTags)
+		apiv1.GET("/tags/:id", withStatic("id", map[string]gin.HandlerFunc{
+			//获取标签统计
+			"stats": v1.GetTagStats,
+			//获取标签云
+			"cloud": v1.GetTagCloud,
+		}, nil))
 		//新建标签
 		apiv1.POST("/tags", v1.AddTag)
 		//更新指定标签
 		apiv1.PUT("/tags/:id", v1.EditTag)
+		//部分更新指定标签
+		apiv1.PATCH("/tags/:id", v1.PatchTag)
 		//删除指定标签
 		apiv1.DELETE("/tags/:id", v1.DeleteTag)
+		apiv1.POST("/tags/:id", withStatic("id", map[string]gin.HandlerFunc{
+			//批量操作标签
+			"batch": v1.BatchTags,
+		}, nil))
+		//合并标签
+		apiv1.POST("/tags/:id/merge", v1.MergeTag)
+		//获取标签别名
+		apiv1.GET("/tags/:id/aliases", v1.GetTagAliases)
+		//新增标签别名
+		apiv1.POST("/tags/:id/aliases", v1.AddTagAlias)
+		//删除标签别名
+		apiv1.DELETE("/tags/:id/aliases/:name", v1.DeleteTagAlias)
 		//导出标签
 		r.POST("/tags/export", v1.ExportTag)
 		//导入标签
@@ -50,16 +85,88 @@ func InitRouter() *gin.Engine {
 		//获取文章列表
 		apiv1.GET("/articles", v1.GetArticles)
 		//获取指定文章
-		apiv1.GET("/articles/:id", v1.GetArticle)
+		apiv1.GET("/articles/:id", withStatic("id", map[string]gin.HandlerFunc{
+			//获取文章按月归档
+			"archive": v1.GetArticleArchive,
+		}, v1.GetArticle))
 		//新建文章
 		apiv1.POST("/articles", v1.AddArticle)
 		//更新指定文章
 		apiv1.PUT("/articles/:id", v1.EditArticle)
+		//部分更新指定文章
+		apiv1.PATCH("/articles/:id", v1.PatchArticle)
 		//删除指定文章
 		apiv1.DELETE("/articles/:id", v1.DeleteArticle)
+		apiv1.POST("/articles/:id", withStatic("id", map[string]gin.HandlerFunc{
+			//批量操作文章
+			"batch": v1.BatchArticles,
+			//导出文章
+			"export": v1.ExportArticle,
+			//导入文章
+			"import": v1.ImportArticle,
+			//解锁密码保护的文章
+			"unlock": v1.UnlockArticle,
+		}, nil))
 		//生成文章海报
-		apiv1.POST("/articles/poster/generate", v1.GenerateArticlePoster)
+		apiv1.POST("/articles/:id/poster", v1.GenerateArticlePoster)
+		//获取海报模板列表
+		apiv1.GET("/posters/templates", v1.GetPosterTemplates)
+		//创建导出任务
+		apiv1.POST("/exports", v1.StartExport)
+		//获取导出任务
+		apiv1.GET("/exports/:id", v1.GetExport)
+		//获取文章译文列表
+		apiv1.GET("/articles/:id/translations", v1.GetArticleTranslations)
+		//新增或更新文章指定语言的译文
+		apiv1.PUT("/articles/:id/translations/:lang", v1.SaveArticleTranslation)
+		//删除文章指定语言的译文
+		apiv1.DELETE("/articles/:id/translations/:lang", v1.DeleteArticleTranslation)
+
+		//获取系列列表
+		apiv1.GET("/series", v1.GetSeriesList)
+		//获取指定系列
+		apiv1.GET("/series/:id", v1.GetSeries)
+		//新建系列
+		apiv1.POST("/series", v1.AddSeries)
+		//更新指定系列
+		apiv1.PUT("/series/:id", v1.EditSeries)
+		//设置系列文章及其顺序
+		apiv1.PUT("/series/:id/articles", v1.EditSeriesArticles)
+		//删除指定系列
+		apiv1.DELETE("/series/:id", v1.DeleteSeries)
+
+		//获取指定作者
+		apiv1.GET("/authors/:id", v1.GetAuthor)
+		//获取指定作者的文章列表
+		apiv1.GET("/authors/:id/articles", v1.GetAuthorArticles)
+		//新建作者资料
+		apiv1.POST("/authors", v1.AddAuthor)
+		//更新指定作者资料
+		apiv1.PUT("/authors/:id", v1.EditAuthor)
+
+		//生成文章预览链接
+		apiv1.POST("/previews", v1.AddPreview)
+
+		//清理未引用的图片
+		apiv1.POST("/uploads/clean", api.CleanUploads)
 	}
 
 	return r
 }
+
+// withStatic serves the static path segments that gin cannot register next to a parameter
+// at the same position, any other value of the parameter is served by handler, or not found if it is nil
+func withStatic(param string, statics map[string]gin.HandlerFunc, handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if static, ok := statics[c.Param(param)]; ok {
+			static(c)
+			return
+		}
+		if handler == nil {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		handler(c)
+	}
+}
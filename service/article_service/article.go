@@ -2,23 +2,51 @@ package article_service
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
 	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
 	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
 	"github.com/EDDYCJY/go-gin-example/service/cache_service"
+	"github.com/EDDYCJY/go-gin-example/service/sitemap_service"
+	"github.com/EDDYCJY/go-gin-example/service/upload_service"
+)
+
+const (
+	BATCH_DELETE    = "delete"
+	BATCH_RESTORE   = "restore"
+	BATCH_SET_STATE = "set_state"
+	BATCH_RETAG     = "retag"
 )
 
 type Article struct {
 	ID            int
 	TagID         int
 	Title         string
+	Slug          string
 	Desc          string
 	Content       string
 	CoverImageUrl string
 	State         int
 	CreatedBy     string
 	ModifiedBy    string
+	Visibility    string
+	Password      string
+
+	// Year and Month restrict the articles read to the ones created in a month of the archive, 0 for any
+	Year  int
+	Month int
+
+	// Lang is the language the article is written in when writing it, and the one to show when reading
+	Lang string
+
+	// Viewer restricts the articles read to the ones the viewer may see, nil reads them all
+	Viewer *Viewer
+
+	// Version is the modification time the article must still have for Edit and Delete to apply, 0 skips the check
+	Version int
 
 	PageNum  int
 	PageSize int
@@ -28,36 +56,89 @@ func (a *Article) Add() error {
 	article := map[string]interface{}{
 		"tag_id":          a.TagID,
 		"title":           a.Title,
+		"slug":            a.Slug,
 		"desc":            a.Desc,
 		"content":         a.Content,
 		"created_by":      a.CreatedBy,
 		"cover_image_url": a.CoverImageUrl,
 		"state":           a.State,
+		"visibility":      a.Visibility,
+		"password":        a.Password,
+		"lang":            a.Lang,
+	}
+	if a.Lang == "" {
+		article["lang"] = util.GetDefaultLang()
+	}
+	if err := preparePassword(0, article); err != nil {
+		return err
 	}
 
-	if err := models.AddArticle(article); err != nil {
+	id, err := models.AddArticle(article)
+	if err != nil {
 		return err
 	}
+	a.ID = id
 
+	if err := syncUploadRefs(id, article); err != nil {
+		return err
+	}
+
+	ClearCache()
+	sitemap_service.Expire()
 	return nil
 }
 
 func (a *Article) Edit() error {
-	return models.EditArticle(a.ID, map[string]interface{}{
-		"tag_id":          a.TagID,
-		"title":           a.Title,
-		"desc":            a.Desc,
-		"content":         a.Content,
-		"cover_image_url": a.CoverImageUrl,
-		"state":           a.State,
-		"modified_by":     a.ModifiedBy,
-	})
+	return a.edit(a.getEditMaps())
+}
+
+// EditFields modify only the given columns of the article
+func (a *Article) EditFields(fields []string) error {
+	maps := a.getEditMaps()
+	data := make(map[string]interface{})
+	for _, field := range fields {
+		if value, ok := maps[field]; ok {
+			data[field] = value
+		}
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	return a.edit(data)
+}
+
+func (a *Article) edit(data map[string]interface{}) error {
+	if err := preparePassword(a.ID, data); err != nil {
+		return err
+	}
+
+	if a.Version > 0 {
+		version, err := models.EditArticleIfUnmodified(a.ID, a.Version, data)
+		if err != nil {
+			return err
+		}
+		if version == 0 {
+			return ErrPreconditionFailed
+		}
+		a.Version = version
+	} else if err := models.EditArticle(a.ID, data); err != nil {
+		return err
+	}
+
+	if err := syncUploadRefs(a.ID, data); err != nil {
+		return err
+	}
+
+	ClearCache()
+	sitemap_service.Expire()
+	return nil
 }
 
 func (a *Article) Get() (*models.Article, error) {
 	var cacheArticle *models.Article
 
-	cache := cache_service.Article{ID: a.ID}
+	cache := cache_service.Article{ID: a.ID, Lang: a.Lang}
 	key := cache.GetArticleKey()
 	if gredis.Exists(key) {
 		data, err := gredis.Get(key)
@@ -73,6 +154,12 @@ func (a *Article) Get() (*models.Article, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := a.translate(article); err != nil {
+		return nil, err
+	}
+	if err := attachAuthors(article); err != nil {
+		return nil, err
+	}
 
 	gredis.Set(key, article, 3600)
 	return article, nil
@@ -83,13 +170,23 @@ func (a *Article) GetAll() ([]*models.Article, error) {
 		articles, cacheArticles []*models.Article
 	)
 
+	conds := a.getConds(models.VISIBILITY_PUBLIC, models.VISIBILITY_PASSWORD)
 	cache := cache_service.Article{
-		TagID: a.TagID,
-		State: a.State,
+		TagID:      a.TagID,
+		State:      a.State,
+		CreatedBy:  a.CreatedBy,
+		Visibility: a.Visibility,
+		Lang:       a.Lang,
+		Year:       a.Year,
+		Month:      a.Month,
 
 		PageNum:  a.PageNum,
 		PageSize: a.PageSize,
 	}
+	if conds != nil {
+		cache.Viewer = a.Viewer.Username
+	}
+	conds = append(conds, a.getFilterConds()...)
 	key := cache.GetArticlesKey()
 	if gredis.Exists(key) {
 		data, err := gredis.Get(key)
@@ -101,25 +198,176 @@ func (a *Article) GetAll() ([]*models.Article, error) {
 		}
 	}
 
-	articles, err := models.GetArticles(a.PageNum, a.PageSize, a.getMaps())
+	articles, err := models.GetArticles(a.PageNum, a.PageSize, a.getMaps(), conds...)
 	if err != nil {
 		return nil, err
 	}
+	if err := a.translateAll(articles); err != nil {
+		return nil, err
+	}
+	if err := attachAuthors(articles...); err != nil {
+		return nil, err
+	}
+	if a.Viewer != nil {
+		a.Viewer.HideProtected(articles)
+	}
 
 	gredis.Set(key, articles, 3600)
 	return articles, nil
 }
 
 func (a *Article) Delete() error {
-	return models.DeleteArticle(a.ID)
+	if a.Version > 0 {
+		deleted, err := models.DeleteArticleIfUnmodified(a.ID, a.Version)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return ErrPreconditionFailed
+		}
+	} else if err := models.DeleteArticle(a.ID); err != nil {
+		return err
+	}
+
+	ClearCache()
+	sitemap_service.Expire()
+	return nil
+}
+
+// Batch applies the action to each article in a single transaction and reports the result of each ID,
+// the articles the viewer may not change being reported as not found
+func (a *Article) Batch(ids []int, action string) ([]*models.BatchResult, error) {
+	editable, err := a.getEditableIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := a.batch(editable, action)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*models.BatchResult, len(results))
+	for _, result := range results {
+		byID[result.ID] = result
+	}
+	merged := make([]*models.BatchResult, 0, len(ids))
+	for _, id := range ids {
+		result, ok := byID[id]
+		if !ok {
+			result = &models.BatchResult{ID: id, Result: models.BATCH_NOT_FOUND}
+		}
+		merged = append(merged, result)
+	}
+
+	return merged, nil
+}
+
+// getEditableIDs leaves out of the IDs the articles the viewer may not change
+func (a *Article) getEditableIDs(ids []int) ([]int, error) {
+	viewer := a.getViewer()
+	articles, err := models.GetArticleOwners(ids)
+	if err != nil {
+		return nil, err
+	}
+	editable := make(map[int]bool, len(articles))
+	for _, article := range articles {
+		editable[article.ID] = viewer.CanEdit(article)
+	}
+
+	filtered := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if editable[id] {
+			filtered = append(filtered, id)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (a *Article) batch(ids []int, action string) ([]*models.BatchResult, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var (
+		results []*models.BatchResult
+		err     error
+	)
+
+	switch action {
+	case BATCH_DELETE:
+		results, err = models.BatchDeleteArticles(ids)
+	case BATCH_RESTORE:
+		results, err = models.BatchRestoreArticles(ids)
+	case BATCH_SET_STATE:
+		results, err = models.BatchEditArticles(ids, map[string]interface{}{
+			"state":       a.State,
+			"modified_by": a.ModifiedBy,
+		})
+	case BATCH_RETAG:
+		results, err = models.BatchEditArticles(ids, map[string]interface{}{
+			"tag_id":      a.TagID,
+			"modified_by": a.ModifiedBy,
+		})
+	default:
+		return nil, fmt.Errorf("unknown batch action: %s", action)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ClearCache()
+	sitemap_service.Expire()
+	return results, nil
 }
 
 func (a *Article) ExistByID() (bool, error) {
 	return models.ExistArticleByID(a.ID)
 }
 
+// ExistBySlug checks if the slug is already used by another article
+func (a *Article) ExistBySlug() (bool, error) {
+	if a.Slug == "" {
+		return false, nil
+	}
+
+	article, err := models.GetArticleBySlug(a.Slug)
+	if err != nil {
+		return false, err
+	}
+
+	return article.ID > 0 && article.ID != a.ID, nil
+}
+
 func (a *Article) Count() (int, error) {
-	return models.GetArticleTotal(a.getMaps())
+	conds := a.getConds(models.VISIBILITY_PUBLIC, models.VISIBILITY_PASSWORD)
+	return models.GetArticleTotal(a.getMaps(), append(conds, a.getFilterConds()...)...)
+}
+
+func (a *Article) getEditMaps() map[string]interface{} {
+	data := map[string]interface{}{
+		"tag_id":          a.TagID,
+		"title":           a.Title,
+		"slug":            a.Slug,
+		"desc":            a.Desc,
+		"content":         a.Content,
+		"cover_image_url": a.CoverImageUrl,
+		"state":           a.State,
+		"modified_by":     a.ModifiedBy,
+	}
+	// the visibility and the password are kept unless given
+	if a.Visibility != "" {
+		data["visibility"] = a.Visibility
+	}
+	if a.Password != "" {
+		data["password"] = a.Password
+	}
+	if a.Lang != "" {
+		data["lang"] = a.Lang
+	}
+
+	return data
 }
 
 func (a *Article) getMaps() map[string]interface{} {
@@ -131,6 +379,63 @@ func (a *Article) getMaps() map[string]interface{} {
 	if a.TagID != -1 {
 		maps["tag_id"] = a.TagID
 	}
+	if a.CreatedBy != "" {
+		maps["created_by"] = a.CreatedBy
+	}
+	if a.Visibility != "" {
+		maps["visibility"] = a.Visibility
+	}
 
 	return maps
 }
+
+// getFilterConds restricts the articles to the requested language and archive month
+func (a *Article) getFilterConds() []*models.Cond {
+	return append(a.getLangConds(), a.getMonthConds()...)
+}
+
+// syncUploadRefs records the uploaded images referenced by the article when the written data may change them
+func syncUploadRefs(id int, data map[string]interface{}) error {
+	cover, hasCover := data["cover_image_url"].(string)
+	content, hasContent := data["content"].(string)
+	if !hasCover && !hasContent {
+		return nil
+	}
+
+	if !hasCover || !hasContent {
+		article, err := models.GetArticle(id)
+		if err != nil {
+			return err
+		}
+		cover, content = article.CoverImageUrl, article.Content
+	}
+
+	return upload_service.SyncRefs(upload_service.REF_ARTICLE, id, cover, content)
+}
+
+// importArticles writes the imported articles in a single transaction, then tracks their uploads
+// and expires what lists them
+func importArticles(imports []*models.ArticleImport) error {
+	if err := models.ImportArticles(imports); err != nil {
+		return err
+	}
+	for _, v := range imports {
+		if err := syncUploadRefs(v.ID, v.Data); err != nil {
+			return err
+		}
+	}
+
+	ClearCache()
+	sitemap_service.Expire()
+
+	return nil
+}
+
+// ClearCache drops every cached article and article list, along with the tag statistics counting them
+func ClearCache() {
+	for _, key := range []string{e.CACHE_ARTICLE, e.CACHE_TAG_STATS} {
+		if err := gredis.LikeDeletes(key); err != nil {
+			logging.Warn(err)
+		}
+	}
+}
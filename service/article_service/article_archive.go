@@ -0,0 +1,94 @@
+package article_service
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/service/cache_service"
+)
+
+// the archive is dropped by every article write, the expiration only bounds how long a missed one lasts
+const archiveCacheExpire = 24 * 3600
+
+type ArchiveBucket struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	Count int `json:"count"`
+}
+
+// GetArchive counts the articles created in each month, latest first, in the archive time zone
+func (a *Article) GetArchive() ([]*ArchiveBucket, error) {
+	var cacheBuckets []*ArchiveBucket
+
+	conds := a.getConds(models.VISIBILITY_PUBLIC, models.VISIBILITY_PASSWORD)
+	cache := cache_service.Article{
+		TagID:     a.TagID,
+		State:     a.State,
+		CreatedBy: a.CreatedBy,
+		Lang:      a.Lang,
+	}
+	if conds != nil {
+		cache.Viewer = a.Viewer.Username
+	}
+	key := cache.GetArchiveKey()
+	if gredis.Exists(key) {
+		data, err := gredis.Get(key)
+		if err != nil {
+			logging.Info(err)
+		} else {
+			json.Unmarshal(data, &cacheBuckets)
+			return cacheBuckets, nil
+		}
+	}
+
+	createdOns, err := models.GetArticleCreatedOns(a.getMaps(), append(conds, a.getLangConds()...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]*ArchiveBucket, 0)
+	byMonth := make(map[int]*ArchiveBucket)
+	for _, createdOn := range createdOns {
+		t := time.Unix(int64(createdOn), 0).In(setting.AppSetting.ArchiveLocation)
+		month := t.Year()*12 + int(t.Month()) - 1
+		bucket, ok := byMonth[month]
+		if !ok {
+			bucket = &ArchiveBucket{Year: t.Year(), Month: int(t.Month())}
+			byMonth[month] = bucket
+			buckets = append(buckets, bucket)
+		}
+		bucket.Count++
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Year != buckets[j].Year {
+			return buckets[i].Year > buckets[j].Year
+		}
+		return buckets[i].Month > buckets[j].Month
+	})
+
+	gredis.Set(key, buckets, archiveCacheExpire)
+	return buckets, nil
+}
+
+// getMonthConds restricts the articles to the ones created in the requested year, or month of it
+func (a *Article) getMonthConds() []*models.Cond {
+	if a.Year == 0 {
+		return nil
+	}
+
+	start := time.Date(a.Year, time.January, 1, 0, 0, 0, 0, setting.AppSetting.ArchiveLocation)
+	end := start.AddDate(1, 0, 0)
+	if a.Month > 0 {
+		start = start.AddDate(0, a.Month-1, 0)
+		end = start.AddDate(0, 1, 0)
+	}
+
+	return []*models.Cond{
+		models.NewCond("created_on >= ? AND created_on < ?", start.Unix(), end.Unix()),
+	}
+}
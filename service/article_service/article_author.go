@@ -0,0 +1,28 @@
+package article_service
+
+import "github.com/EDDYCJY/go-gin-example/models"
+
+// attachAuthors embeds the profile of their author in the articles, articles whose author has none keep a nil one
+func attachAuthors(articles ...*models.Article) error {
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, article := range articles {
+		if article.CreatedBy != "" && !seen[article.CreatedBy] {
+			seen[article.CreatedBy] = true
+			usernames = append(usernames, article.CreatedBy)
+		}
+	}
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	authors, err := models.GetArticleAuthors(usernames)
+	if err != nil {
+		return err
+	}
+	for _, article := range articles {
+		article.Author = authors[article.CreatedBy]
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package article_service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+)
+
+// ErrPreconditionFailed is returned when the article no longer has the version the request was based on
+var ErrPreconditionFailed = errors.New("article has been modified since the requested version")
+
+// GetETag get the entity tag of the article, derived from its ID and modification time
+func GetETag(article *models.Article) string {
+	return fmt.Sprintf(`"%d-%d"`, article.ID, article.ModifiedOn)
+}
+
+// GetViewETag get the entity tag of an article shown along with data of its own, such as a translation or
+// its series navigation, the article part stays first so that the tag is still accepted by If-Match
+func GetViewETag(article *models.Article, view interface{}) string {
+	data, _ := json.Marshal(view)
+	return fmt.Sprintf(`"%d-%d-%08x"`, article.ID, article.ModifiedOn, crc32.ChecksumIEEE(data))
+}
+
+// ParseIfMatch get the version of the article expected by an If-Match header, 0 stands for any version,
+// anything following the ID and modification time in a tag is ignored
+func ParseIfMatch(id int, header string) (int, bool) {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" {
+			return 0, true
+		}
+
+		var articleId, modifiedOn int
+		_, err := fmt.Sscanf(strings.Trim(v, `"`), "%d-%d", &articleId, &modifiedOn)
+		if err == nil && articleId == id && modifiedOn > 0 {
+			return modifiedOn, true
+		}
+	}
+
+	return 0, false
+}
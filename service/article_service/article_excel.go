@@ -0,0 +1,299 @@
+package article_service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/astaxie/beego/validation"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/export"
+	"github.com/EDDYCJY/go-gin-example/pkg/file"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+)
+
+const (
+	FORMAT_XLSX = "xlsx"
+
+	EXCEL_SHEET = "文章信息"
+)
+
+// column indexes of the article sheet
+const (
+	colID = iota
+	colTag
+	colTitle
+	colSlug
+	colDesc
+	colContent
+	colCoverImage
+	colState
+	colCreatedBy
+	colCreatedOn
+	colModifiedBy
+	colModifiedOn
+	colCount
+)
+
+// ErrImportDuplicateSlug is returned when two rows of an imported sheet have the same slug
+var ErrImportDuplicateSlug = errors.New("article sheet has a duplicate slug")
+
+var excelTitles = []string{"ID", "标签", "标题", "别名", "简述", "内容", "封面", "状态", "创建人", "创建时间", "修改人", "修改时间"}
+
+type ExcelImportResult struct {
+	Row    int    `json:"row"`
+	ID     int    `json:"id,omitempty"`
+	Result string `json:"result"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ExportExcel saves the articles matching the constraints as an Excel file named after name, one row per article
+func (a *Article) ExportExcel(name string, progress export.Progress) (string, error) {
+	articles, err := a.getExportArticles()
+	if err != nil {
+		return "", err
+	}
+
+	rows := [][]string{excelTitles}
+	for i, v := range articles {
+		progress.Report(i, len(articles))
+		rows = append(rows, []string{
+			strconv.Itoa(v.ID),
+			v.Tag.Name,
+			v.Title,
+			v.Slug,
+			v.Desc,
+			v.Content,
+			v.CoverImageUrl,
+			strconv.Itoa(v.State),
+			v.CreatedBy,
+			strconv.Itoa(v.CreatedOn),
+			v.ModifiedBy,
+			strconv.Itoa(v.ModifiedOn),
+		})
+	}
+
+	filename := name + export.EXT
+	dirFullPath := export.GetExcelFullPath()
+	err = file.IsNotExistMkDir(dirFullPath)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Create(dirFullPath + filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	// the rows are read back by ImportExcel, so they are written by the same Xlsx format
+	if err := (&export.Xlsx{}).Export(f, EXCEL_SHEET, rows); err != nil {
+		return "", err
+	}
+
+	return filename, nil
+}
+
+// ImportExcel reads the article sheet of an Excel file, rows without an ID create an article and
+// rows with one update it, all of them in a single transaction; a dry run only reports what would happen
+func (a *Article) ImportExcel(r io.Reader, dryRun bool) ([]*ExcelImportResult, error) {
+	rows, err := (&export.Xlsx{}).Import(r, EXCEL_SHEET)
+	if err != nil {
+		return nil, err
+	}
+	if hasDuplicateSlug(rows) {
+		return nil, ErrImportDuplicateSlug
+	}
+
+	var (
+		results = make([]*ExcelImportResult, 0, len(rows))
+		imports []*models.ArticleImport
+		written []*ExcelImportResult
+	)
+	for irow, row := range rows {
+		if irow == 0 || isEmptyRow(row) {
+			continue
+		}
+
+		// rows are reported as numbered in the spreadsheet, the header being row 1
+		result, values, err := a.importExcelRow(irow+1, row)
+		if err != nil {
+			return nil, err
+		}
+		if values != nil {
+			imports = append(imports, &models.ArticleImport{ID: result.ID, Data: values})
+			written = append(written, result)
+		}
+
+		results = append(results, result)
+	}
+
+	if dryRun || len(imports) == 0 {
+		return results, nil
+	}
+
+	if err := importArticles(imports); err != nil {
+		return nil, err
+	}
+	for i, v := range imports {
+		written[i].ID = v.ID
+	}
+
+	return results, nil
+}
+
+// hasDuplicateSlug checks if two rows of the sheet have the same slug
+func hasDuplicateSlug(rows [][]string) bool {
+	seen := make(map[string]bool)
+	for irow, row := range rows {
+		if irow == 0 || colSlug >= len(row) {
+			continue
+		}
+
+		slug := strings.TrimSpace(row[colSlug])
+		if slug == "" {
+			continue
+		}
+		if seen[slug] {
+			return true
+		}
+		seen[slug] = true
+	}
+
+	return false
+}
+
+// importExcelRow checks a single row and returns the values to write for it, nil when it is invalid,
+// only database failures are returned as errors
+func (a *Article) importExcelRow(number int, row []string) (*ExcelImportResult, map[string]interface{}, error) {
+	result := &ExcelImportResult{Row: number}
+	invalid := func(reason string) (*ExcelImportResult, map[string]interface{}, error) {
+		result.Result = IMPORT_INVALID
+		result.Reason = reason
+		return result, nil, nil
+	}
+
+	cells := make([]string, colCount)
+	for i := 0; i < len(row) && i < colCount; i++ {
+		cells[i] = strings.TrimSpace(row[i])
+	}
+	// the content keeps its surrounding whitespace
+	if colContent < len(row) {
+		cells[colContent] = row[colContent]
+	}
+
+	var id int
+	if cells[colID] != "" {
+		n, err := strconv.Atoi(cells[colID])
+		if err != nil || n < 1 {
+			return invalid("ID must be a positive integer")
+		}
+		id = n
+	}
+
+	state, err := strconv.Atoi(cells[colState])
+	if err != nil {
+		return invalid("state must be 0 or 1")
+	}
+
+	author := cells[colCreatedBy]
+	if author == "" {
+		author = a.CreatedBy
+	}
+
+	valid := validation.Validation{}
+	valid.Required(cells[colTag], "tag")
+	valid.Required(cells[colTitle], "title")
+	valid.MaxSize(cells[colTitle], 100, "title")
+	valid.AlphaDash(cells[colSlug], "slug")
+	valid.MaxSize(cells[colSlug], 255, "slug")
+	valid.Required(cells[colDesc], "desc")
+	valid.MaxSize(cells[colDesc], 255, "desc")
+	valid.Required(cells[colContent], "content")
+	valid.MaxSize(cells[colContent], 65535, "content")
+	valid.MaxSize(cells[colCoverImage], 255, "cover_image_url")
+	valid.Range(state, 0, 1, "state")
+	valid.Required(author, "created_by")
+	valid.MaxSize(author, 100, "created_by")
+	if valid.HasErrors() {
+		return invalid(valid.Errors[0].Key + " " + valid.Errors[0].Message)
+	}
+
+	var createdOn int
+	if cells[colCreatedOn] != "" {
+		n, err := strconv.Atoi(cells[colCreatedOn])
+		if err != nil || n < 0 {
+			return invalid("created_on must be a unix timestamp")
+		}
+		createdOn = n
+	}
+
+	tag, err := models.GetTagByName(cells[colTag])
+	if err != nil {
+		return nil, nil, err
+	}
+	if tag.ID == 0 {
+		return invalid(fmt.Sprintf("tag %q does not exist", cells[colTag]))
+	}
+
+	if id > 0 {
+		article, err := models.GetArticle(id)
+		if err != nil {
+			return nil, nil, err
+		}
+		// the articles that cannot be changed are reported as if they did not exist
+		if article.ID == 0 || !a.getViewer().CanEdit(article) {
+			return invalid(fmt.Sprintf("article %d does not exist", id))
+		}
+		result.ID = id
+	}
+
+	if cells[colSlug] != "" {
+		article, err := models.GetArticleBySlug(cells[colSlug])
+		if err != nil {
+			return nil, nil, err
+		}
+		if article.ID > 0 && article.ID != id {
+			return invalid(fmt.Sprintf("slug %q is used by article %d", cells[colSlug], article.ID))
+		}
+	}
+
+	values := map[string]interface{}{
+		"tag_id":          tag.ID,
+		"title":           cells[colTitle],
+		"slug":            cells[colSlug],
+		"desc":            cells[colDesc],
+		"content":         cells[colContent],
+		"cover_image_url": cells[colCoverImage],
+		"state":           state,
+	}
+
+	if id > 0 {
+		result.Result = IMPORT_UPDATED
+		values["modified_by"] = a.CreatedBy
+		return result, values, nil
+	}
+
+	result.Result = IMPORT_CREATED
+	values["created_by"] = author
+	values["lang"] = util.GetDefaultLang()
+	if createdOn > 0 {
+		values["created_on"] = createdOn
+	}
+
+	return result, values, nil
+}
+
+func isEmptyRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+
+	return true
+}
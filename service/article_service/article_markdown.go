@@ -0,0 +1,366 @@
+package article_service
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/export"
+	"github.com/EDDYCJY/go-gin-example/pkg/file"
+	"github.com/EDDYCJY/go-gin-example/pkg/frontmatter"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+)
+
+const (
+	FORMAT_MARKDOWN = "markdown"
+
+	IMPORT_MODE_CREATE = "create"
+	IMPORT_MODE_UPSERT = "upsert"
+
+	IMPORT_CREATED = "created"
+	IMPORT_UPDATED = "updated"
+	IMPORT_SKIPPED = "skipped_existing"
+	IMPORT_INVALID = "invalid"
+
+	MARKDOWN_EXT     = ".md"
+	MARKDOWN_MAXSIZE = 1 << 20
+)
+
+// MarkdownMeta is the front matter of an article, its ID and slug let a re-import find the article again
+// even when it has no slug and its file is named after its ID
+type MarkdownMeta struct {
+	ID         int      `yaml:"id,omitempty"`
+	Slug       string   `yaml:"slug,omitempty"`
+	Title      string   `yaml:"title"`
+	Desc       string   `yaml:"desc"`
+	Tags       []string `yaml:"tags"`
+	State      int      `yaml:"state"`
+	Visibility string   `yaml:"visibility,omitempty"`
+	Lang       string   `yaml:"lang,omitempty"`
+	CoverImage string   `yaml:"cover_image,omitempty"`
+	Author     string   `yaml:"author,omitempty"`
+	CreatedOn  string   `yaml:"created_on,omitempty"`
+	ModifiedOn string   `yaml:"modified_on,omitempty"`
+}
+
+type MarkdownImportResult struct {
+	File   string `json:"file"`
+	Slug   string `json:"slug"`
+	ID     int    `json:"id,omitempty"`
+	Result string `json:"result"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// GetMarkdownName get the file name of the article inside a markdown export
+func GetMarkdownName(article *models.Article) string {
+	slug := article.Slug
+	if slug == "" {
+		slug = "article-" + strconv.Itoa(article.ID)
+	}
+
+	return slug + MARKDOWN_EXT
+}
+
+// ExportMarkdown saves the articles matching the constraints as a zip of markdown files with YAML front matter,
+// named after name
+func (a *Article) ExportMarkdown(name string, progress export.Progress) (string, error) {
+	articles, err := a.getExportArticles()
+	if err != nil {
+		return "", err
+	}
+
+	filename := name + ".zip"
+	dirFullPath := export.GetExcelFullPath()
+	err = file.IsNotExistMkDir(dirFullPath)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Create(dirFullPath + filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if err := WriteMarkdownZip(f, articles, progress); err != nil {
+		return "", err
+	}
+
+	return filename, nil
+}
+
+// WriteMarkdownZip writes one markdown file per article into a zip archive
+func WriteMarkdownZip(w io.Writer, articles []*models.Article, progress export.Progress) error {
+	zw := zip.NewWriter(w)
+	for i, article := range articles {
+		progress.Report(i, len(articles))
+		meta := MarkdownMeta{
+			ID:         article.ID,
+			Slug:       article.Slug,
+			Title:      article.Title,
+			Desc:       article.Desc,
+			Tags:       []string{},
+			State:      article.State,
+			Visibility: article.Visibility,
+			Lang:       article.Lang,
+			CoverImage: article.CoverImageUrl,
+			Author:     article.CreatedBy,
+			CreatedOn:  time.Unix(int64(article.CreatedOn), 0).Format(time.RFC3339),
+			ModifiedOn: time.Unix(int64(article.ModifiedOn), 0).Format(time.RFC3339),
+		}
+		if article.Tag.Name != "" {
+			meta.Tags = append(meta.Tags, article.Tag.Name)
+		}
+
+		data, err := frontmatter.Marshal(&meta, article.Content)
+		if err != nil {
+			return err
+		}
+
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     GetMarkdownName(article),
+			Method:   zip.Deflate,
+			Modified: time.Unix(int64(article.ModifiedOn), 0),
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// ImportMarkdown reads a zip of markdown files and creates an article for each of them, in upsert mode
+// the article with the same ID and slug, or else the same slug, is updated instead; all of them are written
+// in a single transaction, a dry run only reports what would happen
+func (a *Article) ImportMarkdown(r io.ReaderAt, size int64, mode string, dryRun bool) ([]*MarkdownImportResult, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		results  = make([]*MarkdownImportResult, 0, len(zr.File))
+		imports  []*models.ArticleImport
+		written  []*MarkdownImportResult
+		claimers = make(map[string]string)
+	)
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || path.Ext(f.Name) != MARKDOWN_EXT {
+			continue
+		}
+
+		result, values, err := a.importMarkdownFile(f, mode, claimers)
+		if err != nil {
+			return nil, err
+		}
+		if values != nil {
+			imports = append(imports, &models.ArticleImport{ID: result.ID, Data: values})
+			written = append(written, result)
+		}
+
+		results = append(results, result)
+	}
+
+	if dryRun || len(imports) == 0 {
+		return results, nil
+	}
+
+	if err := importArticles(imports); err != nil {
+		return nil, err
+	}
+	for i, v := range imports {
+		written[i].ID = v.ID
+	}
+
+	return results, nil
+}
+
+// importMarkdownFile checks a single file and returns the values to write for it, nil when it is invalid or
+// skipped; claimers holds the files already claiming an article or a slug, so that two files of the archive
+// do not write the same one. Only database failures are returned as errors
+func (a *Article) importMarkdownFile(f *zip.File, mode string, claimers map[string]string) (*MarkdownImportResult, map[string]interface{}, error) {
+	result := &MarkdownImportResult{
+		File: f.Name,
+		Slug: strings.TrimSuffix(path.Base(f.Name), MARKDOWN_EXT),
+	}
+	invalid := func(reason string) (*MarkdownImportResult, map[string]interface{}, error) {
+		result.Result = IMPORT_INVALID
+		result.Reason = reason
+		return result, nil, nil
+	}
+
+	if f.UncompressedSize64 > MARKDOWN_MAXSIZE {
+		return invalid("file is too large")
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return invalid(err.Error())
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(rc, MARKDOWN_MAXSIZE))
+	rc.Close()
+	if err != nil {
+		return invalid(err.Error())
+	}
+
+	var meta MarkdownMeta
+	content, err := frontmatter.Unmarshal(data, &meta)
+	if err != nil {
+		return invalid(err.Error())
+	}
+	if meta.Slug != "" {
+		result.Slug = meta.Slug
+	}
+
+	author := meta.Author
+	if author == "" {
+		author = a.CreatedBy
+	}
+
+	valid := validation.Validation{}
+	valid.AlphaDash(result.Slug, "slug")
+	valid.MaxSize(result.Slug, 255, "slug")
+	valid.Required(meta.Title, "title")
+	valid.MaxSize(meta.Title, 100, "title")
+	valid.Required(meta.Desc, "desc")
+	valid.MaxSize(meta.Desc, 255, "desc")
+	valid.Required(content, "content")
+	valid.MaxSize(content, 65535, "content")
+	valid.Required(meta.Tags, "tags")
+	valid.Range(meta.State, 0, 1, "state")
+	valid.MaxSize(meta.CoverImage, 255, "cover_image")
+	valid.Required(author, "author")
+	valid.MaxSize(author, 100, "author")
+	if valid.HasErrors() {
+		return invalid(valid.Errors[0].Key + " " + valid.Errors[0].Message)
+	}
+
+	switch meta.Visibility {
+	case "", models.VISIBILITY_PUBLIC, models.VISIBILITY_UNLISTED, models.VISIBILITY_PRIVATE, models.VISIBILITY_PASSWORD:
+	default:
+		return invalid(fmt.Sprintf("visibility %q is not supported", meta.Visibility))
+	}
+	if meta.Lang != "" && !util.IsLang(meta.Lang) {
+		return invalid(fmt.Sprintf("lang %q is not supported", meta.Lang))
+	}
+
+	var createdOn int
+	if meta.CreatedOn != "" {
+		t, err := time.Parse(time.RFC3339, meta.CreatedOn)
+		if err != nil {
+			return invalid("created_on " + err.Error())
+		}
+		createdOn = int(t.Unix())
+	}
+
+	tag, err := models.GetTagByName(meta.Tags[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	if tag.ID == 0 {
+		return invalid(fmt.Sprintf("tag %q does not exist", meta.Tags[0]))
+	}
+
+	article, err := findImportedArticle(&meta, result.Slug)
+	if err != nil {
+		return nil, nil, err
+	}
+	if article.ID > 0 {
+		if !a.getViewer().CanEdit(article) {
+			return invalid(fmt.Sprintf("slug %q is used by an article that cannot be changed", result.Slug))
+		}
+		// an article without a slug keeps it empty rather than taking the name of its file
+		result.Slug = article.Slug
+	}
+
+	var claims []string
+	if result.Slug != "" {
+		claims = append(claims, "slug "+result.Slug)
+	}
+	if article.ID > 0 {
+		claims = append(claims, "article "+strconv.Itoa(article.ID))
+	}
+	for _, claim := range claims {
+		if claimer, ok := claimers[claim]; ok {
+			return invalid(fmt.Sprintf("%s is already imported from %s", claim, claimer))
+		}
+	}
+
+	values := map[string]interface{}{
+		"tag_id":          tag.ID,
+		"title":           meta.Title,
+		"slug":            result.Slug,
+		"desc":            meta.Desc,
+		"content":         content,
+		"cover_image_url": meta.CoverImage,
+		"state":           meta.State,
+	}
+	if meta.Visibility != "" {
+		values["visibility"] = meta.Visibility
+	}
+	if meta.Lang != "" {
+		values["lang"] = meta.Lang
+	}
+
+	if article.ID > 0 {
+		result.ID = article.ID
+		if mode != IMPORT_MODE_UPSERT {
+			result.Result = IMPORT_SKIPPED
+			return result, nil, nil
+		}
+
+		result.Result = IMPORT_UPDATED
+		values["modified_by"] = a.CreatedBy
+	} else {
+		result.Result = IMPORT_CREATED
+		values["created_by"] = author
+		if meta.Lang == "" {
+			values["lang"] = util.GetDefaultLang()
+		}
+		if createdOn > 0 {
+			values["created_on"] = createdOn
+		}
+	}
+
+	// passwords are not exported, a protected article keeps the one it already has
+	if err := preparePassword(article.ID, values); err != nil {
+		if err == ErrPasswordNotSet {
+			return invalid("visibility password needs an existing article with a password")
+		}
+		return nil, nil, err
+	}
+
+	for _, claim := range claims {
+		claimers[claim] = f.Name
+	}
+
+	return result, values, nil
+}
+
+// findImportedArticle finds the article a file imports, the one of its ID when it still has the slug of the file,
+// otherwise the one of its slug
+func findImportedArticle(meta *MarkdownMeta, slug string) (*models.Article, error) {
+	if meta.ID > 0 {
+		article, err := models.GetArticle(meta.ID)
+		if err != nil {
+			return nil, err
+		}
+		if article.ID > 0 && article.Slug == meta.Slug {
+			return article, nil
+		}
+	}
+
+	return models.GetArticleBySlug(slug)
+}
@@ -1,30 +1,35 @@
 package article_service
 
 import (
+	"fmt"
 	"image"
-	"image/draw"
-	"image/jpeg"
-	"io/ioutil"
+	"io"
 	"os"
+	"path/filepath"
 
-	"github.com/golang/freetype"
+	"github.com/boombuler/barcode/qr"
 
+	"github.com/EDDYCJY/go-gin-example/models"
 	"github.com/EDDYCJY/go-gin-example/pkg/file"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/poster"
 	"github.com/EDDYCJY/go-gin-example/pkg/qrcode"
-	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/upload"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/upload_service"
 )
 
 type ArticlePoster struct {
 	PosterName string
-	*Article
-	Qr *qrcode.QrCode
+	Article    *models.Article
+	Template   *poster.Template
 }
 
-func NewArticlePoster(posterName string, article *Article, qr *qrcode.QrCode) *ArticlePoster {
+func NewArticlePoster(article *models.Article, template *poster.Template) *ArticlePoster {
 	return &ArticlePoster{
-		PosterName: posterName,
+		PosterName: GetPosterName(article, template),
 		Article:    article,
-		Qr:         qr,
+		Template:   template,
 	}
 }
 
@@ -32,6 +37,15 @@ func GetPosterFlag() string {
 	return "poster"
 }
 
+// GetPosterName gets the poster name of an article drawn with a template, which changes with every edit of either
+func GetPosterName(article *models.Article, template *poster.Template) string {
+	return fmt.Sprintf("%s%d-%d%s", getPosterPrefix(article.ID, template.Name), article.ModifiedOn, template.ModifiedOn, template.GetExt())
+}
+
+func getPosterPrefix(id int, templateName string) string {
+	return fmt.Sprintf("%s-%d-%s-", GetPosterFlag(), id, templateName)
+}
+
 func (a *ArticlePoster) CheckMergedImage(path string) bool {
 	if file.CheckNotExist(path+a.PosterName) == true {
 		return false
@@ -40,158 +54,110 @@ func (a *ArticlePoster) CheckMergedImage(path string) bool {
 	return true
 }
 
-func (a *ArticlePoster) OpenMergedImage(path string) (*os.File, error) {
-	f, err := file.MustOpen(a.PosterName, path)
-	if err != nil {
-		return nil, err
+// Generate draws the poster with its template unless it already is, and returns its path
+func (a *ArticlePoster) Generate() (string, error) {
+	path := qrcode.GetQrCodeFullPath()
+	if a.CheckMergedImage(path) {
+		return path, nil
 	}
 
-	return f, nil
-}
-
-type ArticlePosterBg struct {
-	Name string
-	*ArticlePoster
-	*Rect
-	*Pt
-}
-
-type Rect struct {
-	Name string
-	X0   int
-	Y0   int
-	X1   int
-	Y1   int
-}
-
-type Pt struct {
-	X int
-	Y int
-}
-
-func NewArticlePosterBg(name string, ap *ArticlePoster, rect *Rect, pt *Pt) *ArticlePosterBg {
-	return &ArticlePosterBg{
-		Name:          name,
-		ArticlePoster: ap,
-		Rect:          rect,
-		Pt:            pt,
+	data := &poster.Data{
+		Vars: map[string]string{
+			"title":  a.Article.Title,
+			"desc":   a.Article.Desc,
+			"author": a.getAuthorName(),
+			"url":    util.GetArticleFullUrl(a.Article.ID),
+		},
+		Cover: a.getCover(),
+	}
+	if width, height := a.Template.GetQrSize(); width > 0 {
+		qrImage, err := a.getQr(path, width, height)
+		if err != nil {
+			return "", err
+		}
+		data.Qr = qrImage
 	}
-}
 
-type DrawText struct {
-	JPG    draw.Image
-	Merged *os.File
+	img, err := a.Template.Render(data)
+	if err != nil {
+		return "", err
+	}
 
-	Title string
-	X0    int
-	Y0    int
-	Size0 float64
+	err = file.WriteFile(path, a.PosterName, func(w io.Writer) error {
+		return qrcode.EncodeImage(w, img, a.Template.GetExt(), a.Template.Quality)
+	})
+	if err != nil {
+		return "", err
+	}
 
-	SubTitle string
-	X1       int
-	Y1       int
-	Size1    float64
+	a.removeStalePosters(path)
+	return path, nil
 }
 
-func (a *ArticlePosterBg) DrawPoster(d *DrawText, fontName string) error {
-	fontSource := setting.AppSetting.RuntimeRootPath + setting.AppSetting.FontSavePath + fontName
-	fontSourceBytes, err := ioutil.ReadFile(fontSource)
+// getQr encodes the qr code linking to the article, as a PNG since JPEG artifacts blur its modules
+func (a *ArticlePoster) getQr(path string, width, height int) (image.Image, error) {
+	code := qrcode.NewQrCode(util.GetArticleFullUrl(a.Article.ID), width, height, qr.M, qr.Auto)
+	code.Ext = qrcode.EXT_PNG
+	fileName, _, err := code.Encode(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	trueTypeFont, err := freetype.ParseFont(fontSourceBytes)
+	f, err := os.Open(path + fileName)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer f.Close()
+
+	qrImage, _, err := image.Decode(f)
+	return qrImage, err
+}
+
+func (a *ArticlePoster) getAuthorName() string {
+	if a.Article.Author != nil && a.Article.Author.DisplayName != "" {
+		return a.Article.Author.DisplayName
 	}
 
-	fc := freetype.NewContext()
-	fc.SetDPI(72)
-	fc.SetFont(trueTypeFont)
-	fc.SetFontSize(d.Size0)
-	fc.SetClip(d.JPG.Bounds())
-	fc.SetDst(d.JPG)
-	fc.SetSrc(image.Black)
+	return a.Article.CreatedBy
+}
 
-	pt := freetype.Pt(d.X0, d.Y0)
-	_, err = fc.DrawString(d.Title, pt)
-	if err != nil {
-		return err
+// getCover decodes the cover of the article when it is an uploaded image, external covers are not fetched
+func (a *ArticlePoster) getCover() image.Image {
+	names := upload_service.GetImageNames(a.Article.CoverImageUrl)
+	if len(names) == 0 {
+		return nil
 	}
 
-	fc.SetFontSize(d.Size1)
-	_, err = fc.DrawString(d.SubTitle, freetype.Pt(d.X1, d.Y1))
+	f, err := os.Open(upload.GetImageFullPath() + names[0])
 	if err != nil {
-		return err
+		logging.Warn(err)
+		return nil
 	}
+	defer f.Close()
 
-	err = jpeg.Encode(d.Merged, d.JPG, nil)
+	cover, _, err := image.Decode(f)
 	if err != nil {
-		return err
+		logging.Warn(err)
+		return nil
 	}
 
-	return nil
+	return cover
 }
 
-func (a *ArticlePosterBg) Generate() (string, string, error) {
-	fullPath := qrcode.GetQrCodeFullPath()
-	fileName, path, err := a.Qr.Encode(fullPath)
+// removeStalePosters removes the posters drawn with the template for the previous edits of the article
+func (a *ArticlePoster) removeStalePosters(path string) {
+	names, err := filepath.Glob(path + getPosterPrefix(a.Article.ID, a.Template.Name) + "*")
 	if err != nil {
-		return "", "", err
+		logging.Warn(err)
+		return
 	}
 
-	if !a.CheckMergedImage(path) {
-		mergedF, err := a.OpenMergedImage(path)
-		if err != nil {
-			return "", "", err
+	for _, name := range names {
+		if filepath.Base(name) == a.PosterName {
+			continue
 		}
-		defer mergedF.Close()
-
-		bgF, err := file.MustOpen(a.Name, path)
-		if err != nil {
-			return "", "", err
-		}
-		defer bgF.Close()
-
-		qrF, err := file.MustOpen(fileName, path)
-		if err != nil {
-			return "", "", err
-		}
-		defer qrF.Close()
-
-		bgImage, err := jpeg.Decode(bgF)
-		if err != nil {
-			return "", "", err
-		}
-		qrImage, err := jpeg.Decode(qrF)
-		if err != nil {
-			return "", "", err
-		}
-
-		jpg := image.NewRGBA(image.Rect(a.Rect.X0, a.Rect.Y0, a.Rect.X1, a.Rect.Y1))
-
-		draw.Draw(jpg, jpg.Bounds(), bgImage, bgImage.Bounds().Min, draw.Over)
-		draw.Draw(jpg, jpg.Bounds(), qrImage, qrImage.Bounds().Min.Sub(image.Pt(a.Pt.X, a.Pt.Y)), draw.Over)
-
-		err = a.DrawPoster(&DrawText{
-			JPG:    jpg,
-			Merged: mergedF,
-
-			Title: "Golang Gin 系列文章",
-			X0:    80,
-			Y0:    160,
-			Size0: 42,
-
-			SubTitle: "---煎鱼",
-			X1:       320,
-			Y1:       220,
-			Size1:    36,
-		}, "msyhbd.ttc")
-
-		if err != nil {
-			return "", "", err
+		if err := os.Remove(name); err != nil {
+			logging.Warn(err)
 		}
 	}
-
-	return fileName, path, nil
 }
@@ -0,0 +1,34 @@
+package article_service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+)
+
+// SignPreview get a signed link previewing the article as it is now, until it expires or the article is edited
+func (a *Article) SignPreview(expire time.Duration) (string, time.Time, error) {
+	article, err := models.GetArticle(a.ID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expires := time.Now().Add(expire)
+	token := util.SignToken(getPreviewPayload(article), expires)
+
+	return util.GetPreviewFullUrl(article.ID, token), expires, nil
+}
+
+// CheckPreview checks if the token of a preview link still grants access to the article
+func CheckPreview(article *models.Article, token string) bool {
+	payload, ok := util.ParseSignedToken(token)
+
+	return ok && payload == getPreviewPayload(article)
+}
+
+// getPreviewPayload binds a preview to the modification time of the article, so that editing it revokes the link
+func getPreviewPayload(article *models.Article) string {
+	return fmt.Sprintf("preview-%d-%d", article.ID, article.ModifiedOn)
+}
@@ -0,0 +1,144 @@
+package article_service
+
+import (
+	"errors"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/upload_service"
+)
+
+// ErrSourceLang is returned when an article would be translated into the language it is written in
+var ErrSourceLang = errors.New("article is written in the translation language")
+
+type Translation struct {
+	ArticleID  int
+	Lang       string
+	Title      string
+	Desc       string
+	Content    string
+	ModifiedBy string
+}
+
+// GetTranslations gets every translation of the article
+func (a *Article) GetTranslations() ([]*models.ArticleTranslation, error) {
+	return models.GetArticleTranslations(a.ID)
+}
+
+// Save adds or replaces the translation of the article in the language
+func (t *Translation) Save() error {
+	article, err := models.GetArticle(t.ArticleID)
+	if err != nil {
+		return err
+	}
+	if getSourceLang(article) == t.Lang {
+		return ErrSourceLang
+	}
+
+	id, err := models.SaveArticleTranslation(map[string]interface{}{
+		"article_id":  t.ArticleID,
+		"lang":        t.Lang,
+		"title":       t.Title,
+		"desc":        t.Desc,
+		"content":     t.Content,
+		"modified_by": t.ModifiedBy,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := upload_service.SyncRefs(upload_service.REF_ARTICLE_TRANSLATION, id, t.Content); err != nil {
+		return err
+	}
+
+	ClearCache()
+	return nil
+}
+
+// Delete removes the translation of the article in the language, it reports whether there was one
+func (t *Translation) Delete() (bool, error) {
+	id, err := models.DeleteArticleTranslation(t.ArticleID, t.Lang)
+	if err != nil || id == 0 {
+		return false, err
+	}
+
+	if err := upload_service.SyncRefs(upload_service.REF_ARTICLE_TRANSLATION, id); err != nil {
+		return true, err
+	}
+
+	ClearCache()
+	return true, nil
+}
+
+// translate shows the article in the requested language when it has a translation, keeping its own otherwise
+func (a *Article) translate(article *models.Article) error {
+	article.Lang = getSourceLang(article)
+	if a.Lang == "" || article.Lang == a.Lang {
+		return nil
+	}
+
+	translation, err := models.GetArticleTranslation(article.ID, a.Lang)
+	if err != nil || translation.ID == 0 {
+		return err
+	}
+	applyTranslation(article, translation)
+
+	return nil
+}
+
+// translateAll shows the articles not written in the requested language in their translation, if any
+func (a *Article) translateAll(articles []*models.Article) error {
+	var ids []int
+	for _, article := range articles {
+		article.Lang = getSourceLang(article)
+		if article.Lang != a.Lang {
+			ids = append(ids, article.ID)
+		}
+	}
+	if a.Lang == "" || len(ids) == 0 {
+		return nil
+	}
+
+	translations, err := models.GetTranslationsByLang(ids, a.Lang)
+	if err != nil {
+		return err
+	}
+	for _, article := range articles {
+		if translation, ok := translations[article.ID]; ok {
+			applyTranslation(article, translation)
+		}
+	}
+
+	return nil
+}
+
+// getLangConds restricts the articles to the ones readable in the requested language
+func (a *Article) getLangConds() []*models.Cond {
+	if a.Lang == "" {
+		return nil
+	}
+
+	langs := []string{a.Lang}
+	// articles stored before languages existed have none and are in the default one
+	if a.Lang == util.GetDefaultLang() {
+		langs = append(langs, "")
+	}
+
+	return []*models.Cond{models.NewLangCond(langs, a.Lang)}
+}
+
+// getSourceLang get the language the article is written in
+func getSourceLang(article *models.Article) string {
+	if article.Lang == "" {
+		return util.GetDefaultLang()
+	}
+
+	return article.Lang
+}
+
+func applyTranslation(article *models.Article, translation *models.ArticleTranslation) {
+	article.Lang = translation.Lang
+	article.Title = translation.Title
+	article.Desc = translation.Desc
+	article.Content = translation.Content
+}
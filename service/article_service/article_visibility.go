@@ -0,0 +1,179 @@
+package article_service
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+)
+
+// ErrPasswordNotSet is returned when an article would be password protected without a password
+var ErrPasswordNotSet = errors.New("password protected article has no password")
+
+// Viewer is the user reading articles, identified by the hashed username of the token
+type Viewer struct {
+	Username string
+}
+
+// NewViewer get the viewer authenticated by the claims, claims may be nil
+func NewViewer(claims *util.Claims) *Viewer {
+	if claims == nil {
+		return &Viewer{}
+	}
+
+	return &Viewer{Username: claims.Username}
+}
+
+// IsEditor checks if the viewer is one of the configured editors
+func (v *Viewer) IsEditor() bool {
+	if v.Username == "" {
+		return false
+	}
+
+	for _, editor := range setting.AppSetting.Editors {
+		if util.EncodeMD5(editor) == v.Username {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanManage checks if the viewer is the author of the article or an editor, who see it whatever its visibility
+func (v *Viewer) CanManage(article *models.Article) bool {
+	if v.Username != "" && util.EncodeMD5(article.CreatedBy) == v.Username {
+		return true
+	}
+
+	return v.IsEditor()
+}
+
+// CanEdit checks if the viewer may change or delete the article, the ones that are not public being left
+// to those who manage them
+func (v *Viewer) CanEdit(article *models.Article) bool {
+	if article.Visibility == "" || article.Visibility == models.VISIBILITY_PUBLIC {
+		return true
+	}
+
+	return v.CanManage(article)
+}
+
+// HideProtected blanks the content of the password protected articles the viewer does not manage
+func (v *Viewer) HideProtected(articles []*models.Article) {
+	for _, article := range articles {
+		if article.Visibility == models.VISIBILITY_PASSWORD && !v.CanManage(article) {
+			article.Content = ""
+		}
+	}
+}
+
+// GetUnlockCookieName get the name of the cookie unlocking a password protected article
+func GetUnlockCookieName(id int) string {
+	return "article_unlock_" + strconv.Itoa(id)
+}
+
+// NewUnlockToken get a signed token unlocking the article until it expires or the article is edited
+func NewUnlockToken(article *models.Article) (string, time.Time) {
+	expires := time.Now().Add(setting.AppSetting.ArticleUnlockExpire)
+
+	return util.SignToken(getUnlockPayload(article), expires), expires
+}
+
+// CheckUnlockToken checks if the token still unlocks the article
+func CheckUnlockToken(article *models.Article, token string) bool {
+	payload, ok := util.ParseSignedToken(token)
+
+	return ok && payload == getUnlockPayload(article)
+}
+
+// getUnlockPayload binds the token to the version of the article, so that changing its password locks it again;
+// the password hash itself is not cached with the article
+func getUnlockPayload(article *models.Article) string {
+	return GetUnlockCookieName(article.ID) + "_" + strconv.Itoa(article.ModifiedOn)
+}
+
+// Unlock checks the passphrase of a password protected article and returns the token unlocking it,
+// empty if the passphrase is wrong
+func (a *Article) Unlock(password string) (string, time.Time, error) {
+	article, err := models.GetArticle(a.ID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if article.Visibility != models.VISIBILITY_PASSWORD || article.PasswordHash == "" ||
+		!util.CheckPassword(password, article.PasswordHash) {
+		return "", time.Time{}, nil
+	}
+
+	token, expires := NewUnlockToken(article)
+	return token, expires, nil
+}
+
+// getViewer get the viewer, an anonymous one if there is none
+func (a *Article) getViewer() *Viewer {
+	if a.Viewer == nil {
+		return NewViewer(nil)
+	}
+
+	return a.Viewer
+}
+
+// getExportArticles gets the articles to export, the ones that are not public only if the viewer manages them
+func (a *Article) getExportArticles() ([]*models.Article, error) {
+	a.Viewer = a.getViewer()
+
+	return models.GetArticles(0, 0, a.getMaps(), a.getConds(models.VISIBILITY_PUBLIC)...)
+}
+
+// getConds restricts the articles to the given visibilities, apart from the ones the viewer manages
+func (a *Article) getConds(visibilities ...string) []*models.Cond {
+	if a.Viewer == nil || a.Viewer.IsEditor() {
+		return nil
+	}
+
+	// articles stored before visibilities existed have none and are public
+	visibilities = append(visibilities, "")
+	return []*models.Cond{
+		models.NewCond("(visibility IN (?) OR MD5(created_by) = ?)", visibilities, a.Viewer.Username),
+	}
+}
+
+// preparePassword hashes the password to write and drops the hash of articles no longer password protected
+func preparePassword(id int, data map[string]interface{}) error {
+	if password, ok := data["password"].(string); ok {
+		delete(data, "password")
+		if password != "" {
+			hash, err := util.HashPassword(password)
+			if err != nil {
+				return err
+			}
+			data["password_hash"] = hash
+		}
+	}
+
+	visibility, ok := data["visibility"].(string)
+	if !ok {
+		return nil
+	}
+	if visibility != models.VISIBILITY_PASSWORD {
+		data["password_hash"] = ""
+		return nil
+	}
+	if _, ok := data["password_hash"]; ok {
+		return nil
+	}
+
+	if id > 0 {
+		article, err := models.GetArticle(id)
+		if err != nil {
+			return err
+		}
+		if article.PasswordHash != "" {
+			return nil
+		}
+	}
+
+	return ErrPasswordNotSet
+}
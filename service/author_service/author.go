@@ -0,0 +1,119 @@
+package author_service
+
+import (
+	"errors"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+	"github.com/EDDYCJY/go-gin-example/service/upload_service"
+)
+
+var (
+	// ErrAuthNotFound is returned when a profile would be linked to an account that does not exist
+	ErrAuthNotFound = errors.New("author account does not exist")
+	// ErrAvatarNotUploaded is returned when the avatar is not an image saved by the upload endpoint
+	ErrAvatarNotUploaded = errors.New("author avatar is not an uploaded image")
+)
+
+type Author struct {
+	ID          int
+	AuthID      int
+	DisplayName string
+	Bio         string
+	AvatarUrl   string
+	CreatedBy   string
+	ModifiedBy  string
+}
+
+// Profile is an author along with the username its articles are created by
+type Profile struct {
+	*models.Author
+
+	Username string `json:"username"`
+}
+
+func (a *Author) ExistByID() (bool, error) {
+	return models.ExistAuthorByID(a.ID)
+}
+
+func (a *Author) ExistByAuthID() (bool, error) {
+	return models.ExistAuthorByAuthID(a.AuthID)
+}
+
+func (a *Author) Get() (*Profile, error) {
+	author, err := models.GetAuthor(a.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := models.GetAuth(author.AuthID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Profile{Author: author, Username: auth.Username}, nil
+}
+
+func (a *Author) Add() error {
+	auth, err := models.GetAuth(a.AuthID)
+	if err != nil {
+		return err
+	}
+	if auth.ID == 0 {
+		return ErrAuthNotFound
+	}
+	if err := a.checkAvatar(); err != nil {
+		return err
+	}
+
+	id, err := models.AddAuthor(map[string]interface{}{
+		"auth_id":      a.AuthID,
+		"display_name": a.DisplayName,
+		"bio":          a.Bio,
+		"avatar_url":   a.AvatarUrl,
+		"created_by":   a.CreatedBy,
+	})
+	if err != nil {
+		return err
+	}
+	a.ID = id
+
+	return a.afterWrite()
+}
+
+func (a *Author) Edit() error {
+	if err := a.checkAvatar(); err != nil {
+		return err
+	}
+
+	err := models.EditAuthor(a.ID, map[string]interface{}{
+		"display_name": a.DisplayName,
+		"bio":          a.Bio,
+		"avatar_url":   a.AvatarUrl,
+		"modified_by":  a.ModifiedBy,
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.afterWrite()
+}
+
+// checkAvatar ensures the avatar, if any, comes from the upload endpoint
+func (a *Author) checkAvatar() error {
+	if a.AvatarUrl != "" && len(upload_service.GetImageNames(a.AvatarUrl)) == 0 {
+		return ErrAvatarNotUploaded
+	}
+
+	return nil
+}
+
+// afterWrite keeps the avatar from being cleaned up and drops the articles cached with the former profile
+func (a *Author) afterWrite() error {
+	if err := upload_service.SyncRefs(upload_service.REF_AUTHOR, a.ID, a.AvatarUrl); err != nil {
+		return err
+	}
+
+	article_service.ClearCache()
+	return nil
+}
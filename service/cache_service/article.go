@@ -8,22 +8,42 @@ import (
 )
 
 type Article struct {
-	ID    int
-	TagID int
-	State int
+	ID         int
+	TagID      int
+	State      int
+	CreatedBy  string
+	Visibility string
+	Viewer     string
+	Lang       string
+	Year       int
+	Month      int
 
 	PageNum  int
 	PageSize int
 }
 
 func (a *Article) GetArticleKey() string {
-	return e.CACHE_ARTICLE + "_" + strconv.Itoa(a.ID)
+	key := e.CACHE_ARTICLE + "_" + strconv.Itoa(a.ID)
+	if a.Lang != "" {
+		key += "_" + a.Lang
+	}
+
+	return key
 }
 
 func (a *Article) GetArticlesKey() string {
+	return a.getListKey("LIST")
+}
+
+// GetArchiveKey get the key of the monthly archive of the articles matching the constraints
+func (a *Article) GetArchiveKey() string {
+	return a.getListKey("ARCHIVE")
+}
+
+func (a *Article) getListKey(kind string) string {
 	keys := []string{
 		e.CACHE_ARTICLE,
-		"LIST",
+		kind,
 	}
 
 	if a.ID > 0 {
@@ -35,6 +55,25 @@ func (a *Article) GetArticlesKey() string {
 	if a.State >= 0 {
 		keys = append(keys, strconv.Itoa(a.State))
 	}
+	if a.CreatedBy != "" {
+		// free text, prefixed so that it cannot be taken for another constraint
+		keys = append(keys, "BY"+a.CreatedBy)
+	}
+	if a.Visibility != "" {
+		keys = append(keys, a.Visibility)
+	}
+	if a.Viewer != "" {
+		keys = append(keys, a.Viewer)
+	}
+	if a.Lang != "" {
+		keys = append(keys, a.Lang)
+	}
+	if a.Year > 0 {
+		keys = append(keys, "Y"+strconv.Itoa(a.Year))
+	}
+	if a.Month > 0 {
+		keys = append(keys, "M"+strconv.Itoa(a.Month))
+	}
 	if a.PageNum > 0 {
 		keys = append(keys, strconv.Itoa(a.PageNum))
 	}
@@ -37,3 +37,16 @@ func (t *Tag) GetTagsKey() string {
 
 	return strings.Join(keys, "_")
 }
+
+// GetStatsKey get the key of the statistics of the tags in the state
+func (t *Tag) GetStatsKey() string {
+	keys := []string{
+		e.CACHE_TAG_STATS,
+	}
+
+	if t.State >= 0 {
+		keys = append(keys, strconv.Itoa(t.State))
+	}
+
+	return strings.Join(keys, "_")
+}
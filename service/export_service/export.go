@@ -0,0 +1,194 @@
+package export_service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/export"
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+const (
+	TYPE_TAG     = "tag"
+	TYPE_ARTICLE = "article"
+
+	STATUS_PENDING = "pending"
+	STATUS_RUNNING = "running"
+	STATUS_DONE    = "done"
+	STATUS_FAILED  = "failed"
+)
+
+// maxRunning bounds the exports written at the same time, the others wait as pending
+const maxRunning = 2
+
+var running = make(chan struct{}, maxRunning)
+
+type Job struct {
+	ID            string `json:"id"`
+	Type          string `json:"type"`
+	Format        string `json:"format"`
+	Status        string `json:"status"`
+	Progress      int    `json:"progress"`
+	ExportUrl     string `json:"export_url,omitempty"`
+	ExportSaveUrl string `json:"export_save_url,omitempty"`
+	CreatedOn     int    `json:"created_on"`
+	FinishedOn    int    `json:"finished_on,omitempty"`
+	ExpiresOn     int    `json:"expires_on,omitempty"`
+}
+
+// Task saves an export file named after name and returns its file name, reporting its progress
+type Task func(name string, progress export.Progress) (string, error)
+
+// Start saves a pending job and runs the task in the background
+func Start(typ, format string, task Task) (*Job, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	job := &Job{
+		ID:        hex.EncodeToString(id),
+		Type:      typ,
+		Format:    format,
+		Status:    STATUS_PENDING,
+		CreatedOn: int(time.Now().Unix()),
+	}
+	if err := job.save(); err != nil {
+		return nil, err
+	}
+
+	// the job is returned as started, the copy run in the background being updated meanwhile
+	started := *job
+	go job.run(task)
+	return &started, nil
+}
+
+// Get gets a job by its ID, nil once it has expired
+func Get(id string) (*Job, error) {
+	key := getJobKey(id)
+	if !gredis.Exists(key) {
+		return nil, nil
+	}
+
+	data, err := gredis.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (j *Job) run(task Task) {
+	running <- struct{}{}
+	defer func() { <-running }()
+
+	j.Status = STATUS_RUNNING
+	j.saveOrWarn()
+
+	// the file is named after the job, so that jobs never write the same file
+	filename, err := runTask(task, j.Type+"-"+j.ID, func(done, total int) {
+		if progress := done * 100 / total; progress != j.Progress {
+			j.Progress = progress
+			j.saveOrWarn()
+		}
+	})
+
+	now := time.Now()
+	j.FinishedOn = int(now.Unix())
+	j.ExpiresOn = int(now.Add(setting.AppSetting.ExportExpire).Unix())
+	if err != nil {
+		logging.Error("export_service: job", j.ID, "failed:", err)
+		j.Status = STATUS_FAILED
+	} else {
+		j.Status = STATUS_DONE
+		j.Progress = 100
+		j.ExportUrl = export.GetExcelFullUrl(filename)
+		j.ExportSaveUrl = export.GetExcelPath() + filename
+	}
+	j.saveOrWarn()
+}
+
+// runTask runs the task, turning a panic into an error as nothing recovers it outside of a request
+func runTask(task Task, name string, progress export.Progress) (filename string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return task(name, progress)
+}
+
+func (j *Job) save() error {
+	return gredis.Set(getJobKey(j.ID), j, int(setting.AppSetting.ExportExpire/time.Second))
+}
+
+func (j *Job) saveOrWarn() {
+	if err := j.save(); err != nil {
+		logging.Warn(err)
+	}
+}
+
+func getJobKey(id string) string {
+	return e.CACHE_EXPORT_JOB + "_" + id
+}
+
+// Clean removes the exported files older than expire and returns how many were removed
+func Clean(expire time.Duration) (int, error) {
+	dir := export.GetExcelFullPath()
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	removed := 0
+	before := time.Now().Add(-expire)
+	for _, info := range infos {
+		if info.IsDir() || info.ModTime().After(before) {
+			continue
+		}
+
+		if err := os.Remove(dir + info.Name()); err != nil {
+			logging.Warn(err)
+			continue
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// Schedule runs Clean at every interval, an interval of 0 disables it
+func Schedule(interval, expire time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		for range time.Tick(interval) {
+			removed, err := Clean(expire)
+			if err != nil {
+				logging.Error(err)
+				continue
+			}
+
+			logging.Info("export_service.Clean removed", removed, "files")
+		}
+	}()
+}
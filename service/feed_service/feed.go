@@ -0,0 +1,91 @@
+package feed_service
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/feed"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+)
+
+type Feed struct {
+	TagID  int
+	Format string
+}
+
+// GetETag get the version of the feed, it changes whenever a published article is added, edited or removed
+func (f *Feed) GetETag() (string, time.Time, error) {
+	maps := f.getMaps()
+	lastModified, err := models.GetArticleLastModified(maps, models.NewPublicCond())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	total, err := models.GetArticleTotal(maps, models.NewPublicCond())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	etag := `"` + util.EncodeMD5(f.Format+"_"+strconv.Itoa(f.TagID)+"_"+strconv.Itoa(lastModified)+"_"+strconv.Itoa(total)) + `"`
+
+	return etag, time.Unix(int64(lastModified), 0), nil
+}
+
+func (f *Feed) Generate() ([]byte, error) {
+	articles, err := models.GetRecentArticles(setting.FeedSetting.Size, f.getMaps(), models.NewPublicCond())
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &feed.Feed{
+		Title:       setting.FeedSetting.Title,
+		Link:        setting.AppSetting.PrefixUrl,
+		SelfLink:    f.getSelfLink(),
+		Description: setting.FeedSetting.Description,
+		Author:      setting.FeedSetting.Author,
+	}
+	if f.TagID > 0 {
+		doc.Link = util.GetTagFullUrl(f.TagID)
+	}
+
+	for _, article := range articles {
+		updated := time.Unix(int64(article.ModifiedOn), 0)
+		if updated.After(doc.Updated) {
+			doc.Updated = updated
+		}
+
+		doc.Items = append(doc.Items, &feed.Item{
+			Title:       article.Title,
+			Link:        util.GetArticleFullUrl(article.ID),
+			Description: article.Desc,
+			Author:      article.CreatedBy,
+			Category:    article.Tag.Name,
+			Created:     time.Unix(int64(article.CreatedOn), 0),
+			Updated:     updated,
+		})
+	}
+
+	return doc.Encode(f.Format)
+}
+
+func (f *Feed) getSelfLink() string {
+	link := setting.AppSetting.PrefixUrl + "/feed/"
+	if f.TagID > 0 {
+		link += "tags/" + strconv.Itoa(f.TagID) + "/"
+	}
+
+	return link + f.Format + ".xml"
+}
+
+func (f *Feed) getMaps() map[string]interface{} {
+	maps := make(map[string]interface{})
+	maps["deleted_on"] = 0
+	maps["state"] = 1
+	if f.TagID > 0 {
+		maps["tag_id"] = f.TagID
+	}
+
+	return maps
+}
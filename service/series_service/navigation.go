@@ -0,0 +1,68 @@
+package series_service
+
+import (
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+)
+
+type Navigation struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+	Total    int    `json:"total"`
+	Prev     *Link  `json:"prev"`
+	Next     *Link  `json:"next"`
+}
+
+type Link struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Url   string `json:"url"`
+}
+
+// GetNavigation gets the position of the article in its series with links to its neighbours, nil if it has no series
+func GetNavigation(articleID int) (*Navigation, error) {
+	seriesID, err := models.GetArticleSeriesID(articleID)
+	if err != nil || seriesID == 0 {
+		return nil, err
+	}
+
+	series, err := models.GetSeries(seriesID)
+	if err != nil || series.ID == 0 {
+		return nil, err
+	}
+
+	articles, err := getArticles(seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	nav := &Navigation{
+		ID:    series.ID,
+		Name:  series.Name,
+		Total: len(articles),
+	}
+	for i, article := range articles {
+		if article.ID != articleID {
+			continue
+		}
+
+		nav.Position = i + 1
+		if i > 0 {
+			nav.Prev = newLink(articles[i-1])
+		}
+		if i < len(articles)-1 {
+			nav.Next = newLink(articles[i+1])
+		}
+	}
+
+	return nav, nil
+}
+
+func newLink(article *models.Article) *Link {
+	return &Link{
+		ID:    article.ID,
+		Title: article.Title,
+		Url:   util.GetArticleFullUrl(article.ID),
+	}
+}
@@ -0,0 +1,157 @@
+package series_service
+
+import (
+	"errors"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+)
+
+var (
+	// ErrArticleNotFound is returned when a member of the series is not an existing article
+	ErrArticleNotFound = errors.New("series article does not exist")
+	// ErrArticleInOtherSeries is returned when a member of the series already belongs to another series
+	ErrArticleInOtherSeries = errors.New("series article belongs to another series")
+)
+
+type Series struct {
+	ID         int
+	Name       string
+	Desc       string
+	CreatedBy  string
+	ModifiedBy string
+	ArticleIDs []int
+
+	PageNum  int
+	PageSize int
+}
+
+type SeriesDetail struct {
+	*models.Series
+
+	Articles []*models.Article `json:"articles"`
+}
+
+func (s *Series) ExistByID() (bool, error) {
+	return models.ExistSeriesByID(s.ID)
+}
+
+func (s *Series) ExistByName() (bool, error) {
+	return models.ExistSeriesByName(s.Name)
+}
+
+func (s *Series) Count() (int, error) {
+	return models.GetSeriesTotal(s.getMaps())
+}
+
+func (s *Series) GetAll() ([]*models.Series, error) {
+	return models.GetSeriesList(s.PageNum, s.PageSize, s.getMaps())
+}
+
+// Get gets the series along with its articles in order
+func (s *Series) Get() (*SeriesDetail, error) {
+	series, err := models.GetSeries(s.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	articles, err := getArticles(s.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SeriesDetail{Series: series, Articles: articles}, nil
+}
+
+func (s *Series) Add() error {
+	if err := s.checkArticles(); err != nil {
+		return err
+	}
+
+	return models.AddSeries(map[string]interface{}{
+		"name":       s.Name,
+		"desc":       s.Desc,
+		"created_by": s.CreatedBy,
+	}, s.ArticleIDs)
+}
+
+func (s *Series) Edit() error {
+	return models.EditSeries(s.ID, map[string]interface{}{
+		"name":        s.Name,
+		"desc":        s.Desc,
+		"modified_by": s.ModifiedBy,
+	})
+}
+
+// EditArticles replaces the articles of the series, in the order of ArticleIDs
+func (s *Series) EditArticles() error {
+	if err := s.checkArticles(); err != nil {
+		return err
+	}
+
+	return models.EditSeriesArticles(s.ID, s.ArticleIDs, s.ModifiedBy)
+}
+
+func (s *Series) Delete() error {
+	return models.DeleteSeries(s.ID)
+}
+
+// checkArticles ensures every member is an existing article free to join the series
+func (s *Series) checkArticles() error {
+	if len(s.ArticleIDs) == 0 {
+		return nil
+	}
+
+	existing, err := models.GetExistingArticleIDs(s.ArticleIDs)
+	if err != nil {
+		return err
+	}
+	if len(existing) != len(s.ArticleIDs) {
+		return ErrArticleNotFound
+	}
+
+	conflicts, err := models.GetSeriesConflicts(s.ID, s.ArticleIDs)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return ErrArticleInOtherSeries
+	}
+
+	return nil
+}
+
+// getArticles gets the articles of the series in order, leaving out the drafts and the private ones and
+// the content of the password protected ones
+func getArticles(id int) ([]*models.Article, error) {
+	articles, err := models.GetSeriesArticles(id)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := articles[:0]
+	for _, article := range articles {
+		if article.State != 1 {
+			continue
+		}
+
+		switch article.Visibility {
+		case models.VISIBILITY_PRIVATE:
+			continue
+		case models.VISIBILITY_PASSWORD:
+			article.Content = ""
+		}
+		visible = append(visible, article)
+	}
+
+	return visible, nil
+}
+
+func (s *Series) getMaps() map[string]interface{} {
+	maps := make(map[string]interface{})
+	maps["deleted_on"] = 0
+	if s.Name != "" {
+		maps["name"] = s.Name
+	}
+
+	return maps
+}
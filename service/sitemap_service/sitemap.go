@@ -0,0 +1,186 @@
+package sitemap_service
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/file"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/sitemap"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+)
+
+const (
+	SITEMAP_NAME = "sitemap.xml"
+	ROBOTS_NAME  = "robots.txt"
+)
+
+var mu sync.Mutex
+
+// GetSitemap returns the path of sitemap.xml, regenerating it if it has expired
+func GetSitemap() (string, error) {
+	return ensure(SITEMAP_NAME, Generate)
+}
+
+// GetRobots returns the path of robots.txt, generating it if it does not exist
+func GetRobots() (string, error) {
+	return ensure(ROBOTS_NAME, generateRobots)
+}
+
+// Expire drops the generated sitemap so that it is rebuilt on the next request
+func Expire() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	err := os.Remove(sitemap.GetSitemapFullPath() + SITEMAP_NAME)
+	if err != nil && !os.IsNotExist(err) {
+		logging.Warn(err)
+	}
+}
+
+// Generate writes sitemap.xml, split into child sitemaps when there are too many urls, and robots.txt
+func Generate() error {
+	dir := sitemap.GetSitemapFullPath()
+	if err := file.IsNotExistMkDir(dir); err != nil {
+		return err
+	}
+
+	urls, err := getUrls()
+	if err != nil {
+		return err
+	}
+
+	children, err := filepath.Glob(dir + "sitemap-*.xml")
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := os.Remove(child); err != nil {
+			return err
+		}
+	}
+
+	maxUrls := setting.SitemapSetting.MaxUrls
+	if maxUrls <= 0 || maxUrls > sitemap.MAX_URLS {
+		maxUrls = sitemap.MAX_URLS
+	}
+
+	if len(urls) <= maxUrls {
+		err = file.WriteFile(dir, SITEMAP_NAME, func(w io.Writer) error {
+			return sitemap.WriteUrlSet(w, urls)
+		})
+		if err != nil {
+			return err
+		}
+
+		return generateRobots()
+	}
+
+	var sitemaps []*sitemap.Url
+	for i := 0; i < len(urls); i += maxUrls {
+		end := i + maxUrls
+		if end > len(urls) {
+			end = len(urls)
+		}
+
+		chunk := urls[i:end]
+		name := fmt.Sprintf("sitemap-%d.xml", len(sitemaps)+1)
+		err = file.WriteFile(dir, name, func(w io.Writer) error {
+			return sitemap.WriteUrlSet(w, chunk)
+		})
+		if err != nil {
+			return err
+		}
+
+		child := &sitemap.Url{Loc: sitemap.GetSitemapFullUrl(name)}
+		for _, url := range chunk {
+			if url.LastMod.After(child.LastMod) {
+				child.LastMod = url.LastMod
+			}
+		}
+		sitemaps = append(sitemaps, child)
+	}
+
+	err = file.WriteFile(dir, SITEMAP_NAME, func(w io.Writer) error {
+		return sitemap.WriteIndex(w, sitemaps)
+	})
+	if err != nil {
+		return err
+	}
+
+	return generateRobots()
+}
+
+func ensure(name string, generate func() error) (string, error) {
+	src := sitemap.GetSitemapFullPath() + name
+	if !file.CheckNotExist(src) {
+		return src, nil
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if file.CheckNotExist(src) {
+		if err := generate(); err != nil {
+			return "", err
+		}
+	}
+
+	return src, nil
+}
+
+func generateRobots() error {
+	dir := sitemap.GetSitemapFullPath()
+	if err := file.IsNotExistMkDir(dir); err != nil {
+		return err
+	}
+
+	return file.WriteFile(dir, ROBOTS_NAME, func(w io.Writer) error {
+		return sitemap.WriteRobots(w, &sitemap.Robots{
+			UserAgent: setting.SitemapSetting.RobotsUserAgent,
+			Allow:     setting.SitemapSetting.RobotsAllow,
+			Disallow:  setting.SitemapSetting.RobotsDisallow,
+			Sitemap:   setting.AppSetting.PrefixUrl + "/" + SITEMAP_NAME,
+		})
+	})
+}
+
+// getUrls lists the published tags and the published public articles, filtered as the feed filters them
+func getUrls() ([]*sitemap.Url, error) {
+	maps := map[string]interface{}{
+		"deleted_on": 0,
+		"state":      1,
+	}
+
+	articles, err := models.GetArticleLastMods(maps, models.NewPublicCond())
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := models.GetTags(0, 0, maps)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]*sitemap.Url, 0, len(articles)+len(tags))
+	for _, article := range articles {
+		urls = append(urls, &sitemap.Url{
+			Loc:     util.GetArticleFullUrl(article.ID),
+			LastMod: time.Unix(int64(article.ModifiedOn), 0),
+		})
+	}
+	for _, tag := range tags {
+		urls = append(urls, &sitemap.Url{
+			Loc:     util.GetTagFullUrl(tag.ID),
+			LastMod: time.Unix(int64(tag.ModifiedOn), 0),
+		})
+	}
+
+	return urls, nil
+}
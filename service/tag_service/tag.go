@@ -2,27 +2,64 @@ package tag_service
 
 import (
 	"encoding/json"
-	"io"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
 	"strconv"
-	"time"
 
-	"github.com/360EntSecGroup-Skylar/excelize"
-	"github.com/tealeg/xlsx"
+	"github.com/jinzhu/gorm"
 
 	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
 	"github.com/EDDYCJY/go-gin-example/pkg/export"
 	"github.com/EDDYCJY/go-gin-example/pkg/file"
 	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
 	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
 	"github.com/EDDYCJY/go-gin-example/service/cache_service"
+	"github.com/EDDYCJY/go-gin-example/service/sitemap_service"
+	"github.com/EDDYCJY/go-gin-example/service/upload_service"
 )
 
+const (
+	BATCH_DELETE    = "delete"
+	BATCH_RESTORE   = "restore"
+	BATCH_SET_STATE = "set_state"
+
+	DELETE_REFUSE   = "refuse"
+	DELETE_REASSIGN = "reassign"
+	DELETE_UNTAG    = "untag"
+)
+
+var (
+	// ErrTagInUse is returned when articles still refer to a tag deleted with DELETE_REFUSE
+	ErrTagInUse = errors.New("tag is still used by articles")
+	// ErrReassignNotFound is returned when the tag the articles are reassigned to does not exist
+	ErrReassignNotFound = errors.New("tag reassigned to does not exist")
+	// ErrReassignToSelf is returned when the articles would be reassigned to the deleted tag
+	ErrReassignToSelf = errors.New("tag reassigned to itself")
+	// ErrCoverNotUploaded is returned when the cover image was not saved by the upload endpoint
+	ErrCoverNotUploaded = errors.New("tag cover image is not uploaded")
+)
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// IsColor checks that the color is written as #RRGGBB
+func IsColor(color string) bool {
+	return colorPattern.MatchString(color)
+}
+
 type Tag struct {
-	ID         int
-	Name       string
-	CreatedBy  string
-	ModifiedBy string
-	State      int
+	ID            int
+	Name          string
+	Description   string
+	Color         string
+	CoverImageUrl string
+	SortOrder     int
+	CreatedBy     string
+	ModifiedBy    string
+	State         int
 
 	PageNum  int
 	PageSize int
@@ -37,22 +74,139 @@ func (t *Tag) ExistByID() (bool, error) {
 }
 
 func (t *Tag) Add() error {
-	return models.AddTag(t.Name, t.State, t.CreatedBy)
+	if err := checkCover(t.CoverImageUrl); err != nil {
+		return err
+	}
+
+	id, err := models.AddTag(map[string]interface{}{
+		"name":            t.Name,
+		"description":     t.Description,
+		"color":           t.Color,
+		"cover_image_url": t.CoverImageUrl,
+		"sort_order":      t.SortOrder,
+		"state":           t.State,
+		"created_by":      t.CreatedBy,
+	})
+	if err != nil {
+		return err
+	}
+	t.ID = id
+
+	if err := upload_service.SyncRefs(upload_service.REF_TAG, t.ID, t.CoverImageUrl); err != nil {
+		return err
+	}
+
+	ClearCache()
+	sitemap_service.Expire()
+	return nil
 }
 
 func (t *Tag) Edit() error {
+	return t.edit(t.getEditMaps())
+}
+
+// EditFields modify only the given columns of the tag
+func (t *Tag) EditFields(fields []string) error {
+	maps := t.getEditMaps()
 	data := make(map[string]interface{})
-	data["modified_by"] = t.ModifiedBy
-	data["name"] = t.Name
-	if t.State >= 0 {
-		data["state"] = t.State
+	for _, field := range fields {
+		if value, ok := maps[field]; ok {
+			data[field] = value
+		}
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	return t.edit(data)
+}
+
+func (t *Tag) edit(data map[string]interface{}) error {
+	cover, ok := data["cover_image_url"].(string)
+	if ok {
+		if err := checkCover(cover); err != nil {
+			return err
+		}
+	}
+
+	if err := models.EditTag(t.ID, data); err != nil {
+		return err
+	}
+
+	if ok {
+		if err := upload_service.SyncRefs(upload_service.REF_TAG, t.ID, cover); err != nil {
+			return err
+		}
+	}
+
+	ClearCache()
+	sitemap_service.Expire()
+	return nil
+}
+
+// Delete deletes the tag, what becomes of the articles referring to it depends on the strategy: DELETE_REFUSE fails
+// with ErrTagInUse, DELETE_REASSIGN moves them to the tag toID and DELETE_UNTAG untags them; it returns how many there were
+func (t *Tag) Delete(strategy string, toID int) (int, error) {
+	var (
+		count int
+		err   error
+	)
+
+	switch strategy {
+	case DELETE_REFUSE:
+		count, err = models.DeleteTagIfUnused(t.ID)
+		if err == nil && count > 0 {
+			return count, ErrTagInUse
+		}
+	case DELETE_REASSIGN:
+		if toID == t.ID {
+			return 0, ErrReassignToSelf
+		}
+		count, err = models.DeleteTagMovingArticles(t.ID, toID, t.ModifiedBy)
+		if err == gorm.ErrRecordNotFound {
+			return 0, ErrReassignNotFound
+		}
+	case DELETE_UNTAG:
+		count, err = models.DeleteTagMovingArticles(t.ID, 0, t.ModifiedBy)
+	default:
+		return 0, fmt.Errorf("unknown delete strategy: %s", strategy)
+	}
+	if err != nil {
+		return 0, err
 	}
 
-	return models.EditTag(t.ID, data)
+	ClearCache()
+	sitemap_service.Expire()
+	return count, nil
 }
 
-func (t *Tag) Delete() error {
-	return models.DeleteTag(t.ID)
+// Batch applies the action to each tag in a single transaction and reports the result of each ID
+func (t *Tag) Batch(ids []int, action string) ([]*models.BatchResult, error) {
+	var (
+		results []*models.BatchResult
+		err     error
+	)
+
+	switch action {
+	case BATCH_DELETE:
+		results, err = models.BatchDeleteTags(ids)
+	case BATCH_RESTORE:
+		results, err = models.BatchRestoreTags(ids)
+	case BATCH_SET_STATE:
+		results, err = models.BatchEditTags(ids, map[string]interface{}{
+			"state":       t.State,
+			"modified_by": t.ModifiedBy,
+		})
+	default:
+		return nil, fmt.Errorf("unknown batch action: %s", action)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ClearCache()
+	sitemap_service.Expire()
+	return results, nil
 }
 
 func (t *Tag) Count() (int, error) {
@@ -90,80 +244,64 @@ func (t *Tag) GetAll() ([]models.Tag, error) {
 	return tags, nil
 }
 
-func (t *Tag) Export() (string, error) {
+// Export saves the tags in the format, titled in the language, named after name
+func (t *Tag) Export(name string, format export.Format, lang string, progress export.Progress) (string, error) {
 	tags, err := t.GetAll()
 	if err != nil {
 		return "", err
 	}
 
-	xlsFile := xlsx.NewFile()
-	sheet, err := xlsFile.AddSheet("标签信息")
-	if err != nil {
-		return "", err
-	}
-
-	titles := []string{"ID", "名称", "创建人", "创建时间", "修改人", "修改时间"}
-	row := sheet.AddRow()
-
-	var cell *xlsx.Cell
-	for _, title := range titles {
-		cell = row.AddCell()
-		cell.Value = title
-	}
-
-	for _, v := range tags {
-		values := []string{
+	rows := [][]string{getTitles(lang)}
+	for i, v := range tags {
+		progress.Report(i, len(tags))
+		rows = append(rows, []string{
 			strconv.Itoa(v.ID),
 			v.Name,
 			v.CreatedBy,
 			strconv.Itoa(v.CreatedOn),
 			v.ModifiedBy,
 			strconv.Itoa(v.ModifiedOn),
-		}
-
-		row = sheet.AddRow()
-		for _, value := range values {
-			cell = row.AddCell()
-			cell.Value = value
-		}
+			strconv.Itoa(v.State),
+			v.Description,
+			v.Color,
+			v.CoverImageUrl,
+			strconv.Itoa(v.SortOrder),
+		})
 	}
 
-	time := strconv.Itoa(int(time.Now().Unix()))
-	filename := "tags-" + time + export.EXT
-
+	filename := name + format.Ext()
 	dirFullPath := export.GetExcelFullPath()
 	err = file.IsNotExistMkDir(dirFullPath)
 	if err != nil {
 		return "", err
 	}
 
-	err = xlsFile.Save(dirFullPath + filename)
+	f, err := os.Create(dirFullPath + filename)
 	if err != nil {
 		return "", err
 	}
+	defer f.Close()
 
-	return filename, nil
-}
-
-func (t *Tag) Import(r io.Reader) error {
-	xlsx, err := excelize.OpenReader(r)
-	if err != nil {
-		return err
+	if err := format.Export(f, setting.AppSetting.TagSheetName, rows); err != nil {
+		return "", err
 	}
 
-	rows := xlsx.GetRows("标签信息")
-	for irow, row := range rows {
-		if irow > 0 {
-			var data []string
-			for _, cell := range row {
-				data = append(data, cell)
-			}
+	return filename, nil
+}
 
-			models.AddTag(data[1], 1, data[2])
-		}
+func (t *Tag) getEditMaps() map[string]interface{} {
+	data := make(map[string]interface{})
+	data["modified_by"] = t.ModifiedBy
+	data["name"] = t.Name
+	data["description"] = t.Description
+	data["color"] = t.Color
+	data["cover_image_url"] = t.CoverImageUrl
+	data["sort_order"] = t.SortOrder
+	if t.State >= 0 {
+		data["state"] = t.State
 	}
 
-	return nil
+	return data
 }
 
 func (t *Tag) getMaps() map[string]interface{} {
@@ -179,3 +317,21 @@ func (t *Tag) getMaps() map[string]interface{} {
 
 	return maps
 }
+
+// checkCover ensures the cover image, if any, comes from the upload endpoint
+func checkCover(url string) error {
+	if url != "" && len(upload_service.GetImageNames(url)) == 0 {
+		return ErrCoverNotUploaded
+	}
+
+	return nil
+}
+
+// ClearCache drops every cached tag list, along with the articles embedding the tags
+func ClearCache() {
+	for _, key := range []string{e.CACHE_TAG, e.CACHE_ARTICLE} {
+		if err := gredis.LikeDeletes(key); err != nil {
+			logging.Warn(err)
+		}
+	}
+}
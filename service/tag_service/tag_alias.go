@@ -0,0 +1,83 @@
+package tag_service
+
+import (
+	"errors"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/service/sitemap_service"
+)
+
+var (
+	// ErrMergeSourceNotFound is returned when a tag to merge does not exist
+	ErrMergeSourceNotFound = errors.New("merged tag does not exist")
+	// ErrMergeTargetNotFound is returned when the tag merged into does not exist
+	ErrMergeTargetNotFound = errors.New("tag merged into does not exist")
+	// ErrMergeIntoSource is returned when a tag would be merged into itself
+	ErrMergeIntoSource = errors.New("tag merged into itself")
+	// ErrAliasTaken is returned when an alias is already the name or alias of a tag
+	ErrAliasTaken = errors.New("tag alias is already used")
+)
+
+// Merge moves the articles of the source tags to this tag and deletes the sources, whose names become aliases
+// of this tag; it returns the number of articles moved
+func (t *Tag) Merge(sourceIDs []int) (int, error) {
+	for _, id := range sourceIDs {
+		if id == t.ID {
+			return 0, ErrMergeIntoSource
+		}
+
+		exists, err := models.ExistTagByID(id)
+		if err != nil {
+			return 0, err
+		}
+		if !exists {
+			return 0, ErrMergeSourceNotFound
+		}
+	}
+
+	moved, err := models.MergeTags(t.ID, sourceIDs, t.ModifiedBy)
+	if err == gorm.ErrRecordNotFound {
+		return 0, ErrMergeTargetNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	ClearCache()
+	sitemap_service.Expire()
+	return moved, nil
+}
+
+// GetAliases gets the other names of the tag
+func (t *Tag) GetAliases() ([]*models.TagAlias, error) {
+	return models.GetTagAliases(t.ID)
+}
+
+// AddAlias makes the name another name of the tag
+func (t *Tag) AddAlias(name string) error {
+	exists, err := models.ExistTagByName(name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrAliasTaken
+	}
+
+	// the alias may still be held by a deleted tag
+	alias, err := models.GetTagAlias(name)
+	if err != nil {
+		return err
+	}
+	if alias.ID > 0 {
+		return ErrAliasTaken
+	}
+
+	return models.AddTagAlias(t.ID, name, t.CreatedBy)
+}
+
+// DeleteAlias stops the name from resolving to the tag, it reports whether the tag had it
+func (t *Tag) DeleteAlias(name string) (bool, error) {
+	return models.DeleteTagAlias(t.ID, name)
+}
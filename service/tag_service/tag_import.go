@@ -0,0 +1,235 @@
+package tag_service
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/astaxie/beego/validation"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/export"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/sitemap_service"
+	"github.com/EDDYCJY/go-gin-example/service/upload_service"
+)
+
+const (
+	IMPORT_CREATED           = "created"
+	IMPORT_SKIPPED_DUPLICATE = "skipped_duplicate"
+	IMPORT_INVALID           = "invalid"
+)
+
+// columns of the tag files, the keys are also accepted as titles on import
+var columns = []string{
+	"id", "name", "created_by", "created_on", "modified_by", "modified_on", "state",
+	"description", "color", "cover_image_url", "sort_order",
+}
+
+// titles of the columns in each language
+var titles = map[string][]string{
+	"zh": {"ID", "名称", "创建人", "创建时间", "修改人", "修改时间", "状态", "描述", "颜色", "封面", "排序"},
+	"en": {
+		"ID", "Name", "Created By", "Created On", "Modified By", "Modified On", "State",
+		"Description", "Color", "Cover Image", "Sort Order",
+	},
+}
+
+// ErrImportNoName is returned when the tag file has no name column
+var ErrImportNoName = errors.New("tag file has no name column")
+
+type ImportResult struct {
+	Row    int    `json:"row"`
+	ID     int    `json:"id,omitempty"`
+	Name   string `json:"name"`
+	Result string `json:"result"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Import reads a tag file in the format, its columns found by the titles of its first row in any language, and adds
+// its new tags in a single transaction; rows without a creator fall back to CreatedBy, a dry run only reports what would happen
+func (t *Tag) Import(r io.Reader, format export.Format, dryRun bool) ([]*ImportResult, error) {
+	rows, err := format.Import(r, setting.AppSetting.TagSheetName)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return []*ImportResult{}, nil
+	}
+
+	indexes := make(map[string]int)
+	for i, title := range rows[0] {
+		if column := getColumn(title); column != "" {
+			indexes[column] = i
+		}
+	}
+	if _, ok := indexes["name"]; !ok {
+		return nil, ErrImportNoName
+	}
+	cell := func(row []string, column string) string {
+		i, ok := indexes[column]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	var (
+		results []*ImportResult
+		created []*ImportResult
+		data    []map[string]interface{}
+	)
+	seen := make(map[string]bool)
+	for irow, row := range rows[1:] {
+		cells := make(map[string]string, len(columns))
+		empty := true
+		for _, column := range columns {
+			cells[column] = cell(row, column)
+			if cells[column] != "" {
+				empty = false
+			}
+		}
+		if empty {
+			continue
+		}
+
+		// rows are reported as numbered in the spreadsheet, the header being row 1
+		result := &ImportResult{Row: irow + 2, Name: cells["name"]}
+		results = append(results, result)
+
+		values, reason, err := t.checkImportRow(cells, seen)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case reason != "":
+			result.Result = IMPORT_INVALID
+			result.Reason = reason
+		case values == nil:
+			result.Result = IMPORT_SKIPPED_DUPLICATE
+		default:
+			result.Result = IMPORT_CREATED
+			created = append(created, result)
+			data = append(data, values)
+		}
+	}
+
+	if dryRun || len(data) == 0 {
+		return results, nil
+	}
+
+	ids, err := models.AddTags(data)
+	if err != nil {
+		return nil, err
+	}
+	for i, result := range created {
+		result.ID = ids[i]
+		if err := upload_service.SyncRefs(upload_service.REF_TAG, ids[i], data[i]["cover_image_url"].(string)); err != nil {
+			return nil, err
+		}
+	}
+
+	ClearCache()
+	sitemap_service.Expire()
+	return results, nil
+}
+
+// checkImportRow validates a row, it returns the tag to add, nil for a duplicate, or the reason the row is invalid;
+// only database failures are returned as errors
+func (t *Tag) checkImportRow(cells map[string]string, seen map[string]bool) (map[string]interface{}, string, error) {
+	name, color, cover := cells["name"], cells["color"], cells["cover_image_url"]
+	createdBy := cells["created_by"]
+	if createdBy == "" {
+		createdBy = t.CreatedBy
+	}
+
+	state := 1
+	if cells["state"] != "" {
+		n, err := strconv.Atoi(cells["state"])
+		if err != nil {
+			return nil, "state must be 0 or 1", nil
+		}
+		state = n
+	}
+
+	sortOrder := 0
+	if cells["sort_order"] != "" {
+		n, err := strconv.Atoi(cells["sort_order"])
+		if err != nil {
+			return nil, "sort_order must be an integer", nil
+		}
+		sortOrder = n
+	}
+
+	valid := validation.Validation{}
+	valid.Required(name, "name")
+	valid.MaxSize(name, 100, "name")
+	valid.MaxSize(cells["description"], 255, "description")
+	valid.MaxSize(cover, 255, "cover_image_url")
+	valid.Min(sortOrder, 0, "sort_order")
+	valid.Required(createdBy, "created_by")
+	valid.MaxSize(createdBy, 100, "created_by")
+	valid.Range(state, 0, 1, "state")
+	if valid.HasErrors() {
+		return nil, valid.Errors[0].Key + " " + valid.Errors[0].Message, nil
+	}
+	if color != "" && !IsColor(color) {
+		return nil, "color must be written as #RRGGBB", nil
+	}
+	if checkCover(cover) != nil {
+		return nil, "cover_image_url must be an uploaded image", nil
+	}
+
+	// names are compared as the database collation does, regardless of case
+	key := strings.ToLower(name)
+	if seen[key] {
+		return nil, "", nil
+	}
+	seen[key] = true
+
+	exists, err := models.ExistTagByName(name)
+	if err != nil || exists {
+		return nil, "", err
+	}
+
+	return map[string]interface{}{
+		"name":            name,
+		"description":     cells["description"],
+		"color":           color,
+		"cover_image_url": cover,
+		"sort_order":      sortOrder,
+		"state":           state,
+		"created_by":      createdBy,
+	}, "", nil
+}
+
+// getTitles returns the titles of the columns in the language, or the default one
+func getTitles(lang string) []string {
+	if v, ok := titles[lang]; ok {
+		return v
+	}
+	if v, ok := titles[util.GetDefaultLang()]; ok {
+		return v
+	}
+
+	return columns
+}
+
+// getColumn returns the column of a title in any language, regardless of case
+func getColumn(title string) string {
+	title = strings.TrimSpace(title)
+	for i, column := range columns {
+		if strings.EqualFold(title, column) {
+			return column
+		}
+		for _, v := range titles {
+			if strings.EqualFold(title, v[i]) {
+				return column
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,122 @@
+package tag_service
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/service/cache_service"
+)
+
+const statsCacheExpire = 3600
+
+type Stat struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Color      string `json:"color"`
+	State      int    `json:"state"`
+	Count      int    `json:"count"`
+	LastUsedOn int    `json:"last_used_on"`
+}
+
+type CloudTag struct {
+	ID     int     `json:"id"`
+	Name   string  `json:"name"`
+	Color  string  `json:"color"`
+	Count  int     `json:"count"`
+	Weight float64 `json:"weight"`
+}
+
+// GetStats gets the tags in the state with the number of their published articles, the most used first;
+// they are cached until a tag or an article is written
+func (t *Tag) GetStats() ([]*Stat, error) {
+	var stats []*Stat
+
+	cache := cache_service.Tag{State: t.State}
+	key := cache.GetStatsKey()
+	if gredis.Exists(key) {
+		data, err := gredis.Get(key)
+		if err != nil {
+			logging.Info(err)
+		} else {
+			json.Unmarshal(data, &stats)
+			return stats, nil
+		}
+	}
+
+	tags, err := models.GetTags(0, 0, t.getMaps())
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := models.GetTagStats(map[string]interface{}{
+		"deleted_on": 0,
+		"state":      1,
+	}, models.NewPublicCond())
+	if err != nil {
+		return nil, err
+	}
+	byTag := make(map[int]*models.TagStat, len(counts))
+	for _, v := range counts {
+		byTag[v.TagID] = v
+	}
+
+	stats = make([]*Stat, 0, len(tags))
+	for _, tag := range tags {
+		stat := &Stat{ID: tag.ID, Name: tag.Name, Color: tag.Color, State: tag.State}
+		if v, ok := byTag[tag.ID]; ok {
+			stat.Count = v.Count
+			stat.LastUsedOn = v.LastUsedOn
+		}
+		stats = append(stats, stat)
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].Name < stats[j].Name
+	})
+
+	gredis.Set(key, stats, statsCacheExpire)
+	return stats, nil
+}
+
+// GetCloud gets the tags in the state having published articles by name, weighted between 0 and 1
+// on a logarithmic scale of their counts so that a few popular tags do not flatten the others
+func (t *Tag) GetCloud() ([]*CloudTag, error) {
+	stats, err := t.GetStats()
+	if err != nil {
+		return nil, err
+	}
+
+	cloud := make([]*CloudTag, 0, len(stats))
+	min, max := 0, 0
+	for _, v := range stats {
+		if v.Count == 0 {
+			continue
+		}
+		if min == 0 || v.Count < min {
+			min = v.Count
+		}
+		if v.Count > max {
+			max = v.Count
+		}
+		cloud = append(cloud, &CloudTag{ID: v.ID, Name: v.Name, Color: v.Color, Count: v.Count})
+	}
+
+	for _, v := range cloud {
+		v.Weight = 1
+		if max > min {
+			weight := (math.Log(float64(v.Count)) - math.Log(float64(min))) / (math.Log(float64(max)) - math.Log(float64(min)))
+			v.Weight = math.Round(weight*1000) / 1000
+		}
+	}
+	sort.Slice(cloud, func(i, j int) bool {
+		return cloud[i].Name < cloud[j].Name
+	})
+
+	return cloud, nil
+}
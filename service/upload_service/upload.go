@@ -0,0 +1,191 @@
+package upload_service
+
+import (
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/upload"
+)
+
+const (
+	REF_ARTICLE             = "article"
+	REF_ARTICLE_TRANSLATION = "article_translation"
+	REF_AUTHOR              = "author"
+	REF_TAG                 = "tag"
+
+	// articles are scanned in pages of this size when references are rebuilt
+	scanSize = 100
+)
+
+var cleanMutex sync.Mutex
+
+type CleanResult struct {
+	Files  int      `json:"files"`
+	Bytes  int64    `json:"bytes"`
+	Failed int      `json:"failed"`
+	Names  []string `json:"names"`
+}
+
+// Record keeps track of an image saved by an upload
+func Record(name string, size int64, createdBy string) error {
+	return models.AddUpload(map[string]interface{}{
+		"name":       name,
+		"path":       upload.GetImagePath(),
+		"size":       size,
+		"created_by": createdBy,
+	})
+}
+
+// GetImageNames get the uploaded images referenced in the texts, by their full or relative url
+func GetImageNames(texts ...string) []string {
+	re := regexp.MustCompile(regexp.QuoteMeta(upload.GetImagePath()) + `([\w.-]+)`)
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, match := range re.FindAllStringSubmatch(text, -1) {
+			if name := match[1]; !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
+}
+
+// SyncRefs records the uploaded images referenced in the texts of an entity
+func SyncRefs(refType string, refID int, texts ...string) error {
+	return models.SetUploadRefs(refType, refID, GetImageNames(texts...))
+}
+
+// Clean deletes the uploaded images that nothing references and that are older than the grace period,
+// the references of every article are rebuilt first so that a missed update cannot lose an image in use
+func Clean(grace time.Duration) (*CleanResult, error) {
+	cleanMutex.Lock()
+	defer cleanMutex.Unlock()
+
+	if err := rebuildArticleRefs(); err != nil {
+		return nil, err
+	}
+	if err := recordUntracked(); err != nil {
+		return nil, err
+	}
+
+	uploads, err := models.GetUnreferencedUploads(int(time.Now().Add(-grace).Unix()))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CleanResult{Names: []string{}}
+	dir := upload.GetImageFullPath()
+	for _, v := range uploads {
+		src := dir + v.Name
+		info, err := os.Stat(src)
+		if err == nil {
+			err = os.Remove(src)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			logging.Warn(err)
+			result.Failed++
+			continue
+		}
+
+		if err := models.DeleteUpload(v.ID); err != nil {
+			return nil, err
+		}
+		if info != nil {
+			result.Files++
+			result.Bytes += info.Size()
+			result.Names = append(result.Names, v.Name)
+		}
+	}
+
+	return result, nil
+}
+
+// Schedule runs Clean at every interval, an interval of 0 disables it
+func Schedule(interval, grace time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		for range time.Tick(interval) {
+			result, err := Clean(grace)
+			if err != nil {
+				logging.Error(err)
+				continue
+			}
+
+			logging.Info("upload_service.Clean removed", result.Files, "files, reclaimed", result.Bytes, "bytes,", result.Failed, "failed")
+		}
+	}()
+}
+
+func rebuildArticleRefs() error {
+	refs := make(map[int][]string)
+	for lastID := 0; ; {
+		articles, err := models.GetArticlesAfter(lastID, scanSize)
+		if err != nil {
+			return err
+		}
+
+		for _, article := range articles {
+			if names := GetImageNames(article.CoverImageUrl, article.Content); len(names) > 0 {
+				refs[article.ID] = names
+			}
+			lastID = article.ID
+		}
+
+		if len(articles) < scanSize {
+			break
+		}
+	}
+
+	return models.ReplaceUploadRefs(REF_ARTICLE, refs)
+}
+
+// recordUntracked records the images saved before uploads were tracked, dated by their modification time
+func recordUntracked() error {
+	files, err := ioutil.ReadDir(upload.GetImageFullPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	names, err := models.GetUploadNames(upload.GetImagePath())
+	if err != nil {
+		return err
+	}
+	tracked := make(map[string]bool, len(names))
+	for _, name := range names {
+		tracked[name] = true
+	}
+
+	for _, f := range files {
+		if f.IsDir() || tracked[f.Name()] || !upload.CheckImageExt(f.Name()) {
+			continue
+		}
+
+		err := models.AddUpload(map[string]interface{}{
+			"name":       f.Name(),
+			"path":       upload.GetImagePath(),
+			"size":       f.Size(),
+			"created_by": "",
+			"created_on": int(f.ModTime().Unix()),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}